@@ -0,0 +1,171 @@
+package tbot
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ChatAdministratorRights are rights of an administrator in a chat.
+// Promoting with all rights false demotes the administrator.
+type ChatAdministratorRights struct {
+	// IsAnonymous True, if the administrator's presence in the chat is hidden
+	IsAnonymous bool `json:"is_anonymous"`
+	// CanManageChat True, if the administrator can access the chat event log, statistics, members and ignore slow mode
+	CanManageChat bool `json:"can_manage_chat"`
+	// CanDeleteMessages True, if the administrator can delete messages of other users
+	CanDeleteMessages bool `json:"can_delete_messages"`
+	// CanManageVideoChats True, if the administrator can manage video chats
+	CanManageVideoChats bool `json:"can_manage_video_chats"`
+	// CanRestrictMembers True, if the administrator can restrict, ban or unban chat members
+	CanRestrictMembers bool `json:"can_restrict_members"`
+	// CanPromoteMembers True, if the administrator can add new administrators with a subset of their own rights
+	CanPromoteMembers bool `json:"can_promote_members"`
+	// CanChangeInfo True, if the user is allowed to change the chat title, photo and other settings
+	CanChangeInfo bool `json:"can_change_info"`
+	// CanInviteUsers True, if the user is allowed to invite new users to the chat
+	CanInviteUsers bool `json:"can_invite_users"`
+	// CanPostMessages True, if the administrator can post in the channel, channels only
+	CanPostMessages bool `json:"can_post_messages,omitempty"`
+	// CanEditMessages True, if the administrator can edit messages of other users and pin messages, channels only
+	CanEditMessages bool `json:"can_edit_messages,omitempty"`
+	// CanPinMessages True, if the user is allowed to pin messages, groups and supergroups only
+	CanPinMessages bool `json:"can_pin_messages,omitempty"`
+	// CanManageTopics True, if the user is allowed to create, rename, close, and reopen forum topics, supergroups only
+	CanManageTopics bool `json:"can_manage_topics,omitempty"`
+}
+
+var (
+	// OptUntilDate sets when the ban or restriction is lifted. Users banned or
+	// restricted for more than 366 days or less than 30 seconds are so forever.
+	OptUntilDate = func(t time.Time) sendOption {
+		return func(r url.Values) {
+			r.Set("until_date", strconv.FormatInt(t.Unix(), 10))
+		}
+	}
+	// OptRevokeMessages deletes all messages of the banned user from the chat
+	OptRevokeMessages = func(r url.Values) { r.Set("revoke_messages", "true") }
+	// OptOnlyIfBanned makes UnbanChatMember do nothing for users who are not banned,
+	// otherwise a member is removed from the chat
+	OptOnlyIfBanned = func(r url.Values) { r.Set("only_if_banned", "true") }
+	// OptIndependentPermissions applies permissions as given, otherwise
+	// can_send_other_messages and can_add_web_page_previews imply the
+	// permissions to send audios, documents, photos, videos, video notes and voice notes
+	OptIndependentPermissions = func(r url.Values) { r.Set("use_independent_chat_permissions", "true") }
+)
+
+// BanChatMember bans user in a group, supergroup or channel. Available options:
+//   - OptUntilDate(t time.Time)
+//   - OptRevokeMessages
+func (c *Client) BanChatMember(chatID string, userID int, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("user_id", strconv.Itoa(userID))
+	for _, opt := range opts {
+		opt(req)
+	}
+	var banned bool
+	return c.sendRequest("/banChatMember", req, &banned)
+}
+
+// UnbanChatMember unbans previously banned user. Available options:
+//   - OptOnlyIfBanned
+func (c *Client) UnbanChatMember(chatID string, userID int, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("user_id", strconv.Itoa(userID))
+	for _, opt := range opts {
+		opt(req)
+	}
+	var unbanned bool
+	return c.sendRequest("/unbanChatMember", req, &unbanned)
+}
+
+// RestrictChatMember sets permissions of user in a supergroup, permissions
+// which are false are taken away. Available options:
+//   - OptUntilDate(t time.Time)
+//   - OptIndependentPermissions
+func (c *Client) RestrictChatMember(chatID string, userID int, permissions ChatPermissions, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("user_id", strconv.Itoa(userID))
+	req.Set("permissions", structString(permissions))
+	for _, opt := range opts {
+		opt(req)
+	}
+	var restricted bool
+	return c.sendRequest("/restrictChatMember", req, &restricted)
+}
+
+// PromoteChatMember promotes or demotes user in a supergroup or channel
+func (c *Client) PromoteChatMember(chatID string, userID int, rights ChatAdministratorRights) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("user_id", strconv.Itoa(userID))
+	req.Set("is_anonymous", strconv.FormatBool(rights.IsAnonymous))
+	req.Set("can_manage_chat", strconv.FormatBool(rights.CanManageChat))
+	req.Set("can_delete_messages", strconv.FormatBool(rights.CanDeleteMessages))
+	req.Set("can_manage_video_chats", strconv.FormatBool(rights.CanManageVideoChats))
+	req.Set("can_restrict_members", strconv.FormatBool(rights.CanRestrictMembers))
+	req.Set("can_promote_members", strconv.FormatBool(rights.CanPromoteMembers))
+	req.Set("can_change_info", strconv.FormatBool(rights.CanChangeInfo))
+	req.Set("can_invite_users", strconv.FormatBool(rights.CanInviteUsers))
+	// rights of channels, groups or forums only are sent when granted
+	if rights.CanPostMessages {
+		req.Set("can_post_messages", "true")
+	}
+	if rights.CanEditMessages {
+		req.Set("can_edit_messages", "true")
+	}
+	if rights.CanPinMessages {
+		req.Set("can_pin_messages", "true")
+	}
+	if rights.CanManageTopics {
+		req.Set("can_manage_topics", "true")
+	}
+	var promoted bool
+	return c.sendRequest("/promoteChatMember", req, &promoted)
+}
+
+// SetChatAdministratorCustomTitle sets title of an administrator promoted by the bot
+func (c *Client) SetChatAdministratorCustomTitle(chatID string, userID int, title string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("user_id", strconv.Itoa(userID))
+	req.Set("custom_title", title)
+	var set bool
+	return c.sendRequest("/setChatAdministratorCustomTitle", req, &set)
+}
+
+// BanChatSenderChat bans a channel chat in a supergroup or channel, its
+// owner cannot send messages on behalf of any of their channels
+func (c *Client) BanChatSenderChat(chatID string, senderChatID int) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("sender_chat_id", strconv.Itoa(senderChatID))
+	var banned bool
+	return c.sendRequest("/banChatSenderChat", req, &banned)
+}
+
+// UnbanChatSenderChat unbans a previously banned channel chat
+func (c *Client) UnbanChatSenderChat(chatID string, senderChatID int) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("sender_chat_id", strconv.Itoa(senderChatID))
+	var unbanned bool
+	return c.sendRequest("/unbanChatSenderChat", req, &unbanned)
+}
+
+// SetChatPermissions sets default permissions of all members of a group or
+// supergroup. Available options:
+//   - OptIndependentPermissions
+func (c *Client) SetChatPermissions(chatID string, permissions ChatPermissions, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("permissions", structString(permissions))
+	for _, opt := range opts {
+		opt(req)
+	}
+	var set bool
+	return c.sendRequest("/setChatPermissions", req, &set)
+}
@@ -0,0 +1,70 @@
+package tbot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestClient_ChatAdministration(t *testing.T) {
+	var method string
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		method, form = r.URL.Path, r.Form
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+	c := NewClient("token", srv.URL)
+
+	tests := []struct {
+		name   string
+		call   func() error
+		method string
+		want   map[string]string
+	}{
+		{
+			name: "ban",
+			call: func() error {
+				return c.BanChatMember("-1", 7, OptUntilDate(time.Unix(1700000000, 0)), OptRevokeMessages)
+			},
+			method: "/bottoken/banChatMember",
+			want:   map[string]string{"chat_id": "-1", "user_id": "7", "until_date": "1700000000", "revoke_messages": "true"},
+		},
+		{
+			name: "restrict",
+			call: func() error {
+				return c.RestrictChatMember("-1", 7, ChatPermissions{CanSendMessages: true, CanSendPhotos: true}, OptIndependentPermissions)
+			},
+			method: "/bottoken/restrictChatMember",
+			want: map[string]string{
+				"permissions":                      `{"can_send_messages":true,"can_send_photos":true}`,
+				"use_independent_chat_permissions": "true",
+			},
+		},
+		{
+			name:   "promote",
+			call:   func() error { return c.PromoteChatMember("-1", 7, ChatAdministratorRights{CanDeleteMessages: true}) },
+			method: "/bottoken/promoteChatMember",
+			want:   map[string]string{"can_delete_messages": "true", "can_promote_members": "false", "can_pin_messages": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if method != tt.method {
+				t.Errorf("method = %s, want %s", method, tt.method)
+			}
+			for key, want := range tt.want {
+				if got := form.Get(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
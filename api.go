@@ -1,6 +1,7 @@
 package tbot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -23,7 +24,31 @@ type apiResponse struct {
 	Result      json.RawMessage     `json:"result"`
 	Description string              `json:"description"`
 	ErrorCode   int                 `json:"error_code"`
-	Parameter   *responseParameters `json:"parameter,omitempty"`
+	Parameters  *responseParameters `json:"parameters,omitempty"`
+}
+
+// APIError is an error returned by the Bot API
+type APIError struct {
+	Code        int
+	Description string
+	// MigrateToChatID is set if the group was migrated to a supergroup with this id
+	MigrateToChatID int
+	// RetryAfter is the number of seconds to wait before repeating the request
+	// if flood control was exceeded
+	RetryAfter int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%d : %s", e.Code, e.Description)
+}
+
+func (r *apiResponse) err() error {
+	e := &APIError{Code: r.ErrorCode, Description: r.Description}
+	if r.Parameters != nil {
+		e.MigrateToChatID = r.Parameters.MigrateToChatID
+		e.RetryAfter = r.Parameters.RetryAfter
+	}
+	return e
 }
 
 var netTransport = &http.Transport{
@@ -34,14 +59,24 @@ var netTransport = &http.Transport{
 }
 
 func (c *Client) sendRequest(method string, request url.Values, response any) error {
+	return c.sendRequestContext(context.Background(), method, request, response)
+}
+
+func (c *Client) sendRequestContext(ctx context.Context, method string, request url.Values, response any) error {
+	return c.withMigration(request, func(request url.Values) error {
+		return c.postRequest(ctx, method, request, response)
+	})
+}
+
+func (c *Client) postRequest(ctx context.Context, method string, request url.Values, response any) error {
 	var err error
 	var req *http.Request
 	var resp *http.Response
 	endPoint := fmt.Sprintf(c.url, method)
 	if request == nil {
-		req, err = http.NewRequest(http.MethodPost, endPoint, nil)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endPoint, nil)
 	} else {
-		req, err = http.NewRequest(http.MethodPost, endPoint, strings.NewReader(request.Encode()))
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endPoint, strings.NewReader(request.Encode()))
 
 	}
 	if err != nil {
@@ -77,7 +112,7 @@ func (c *Client) sendRequest(method string, request url.Values, response any) er
 		}
 
 		if !apiResp.OK {
-			return fmt.Errorf("%d : %s", apiResp.ErrorCode, apiResp.Description)
+			return apiResp.err()
 		}
 
 		return json.Unmarshal(apiResp.Result, response)
@@ -90,6 +125,12 @@ func (c *Client) sendRequest(method string, request url.Values, response any) er
 }
 
 func (c *Client) sendRequestWithFiles(method string, request url.Values, response any, files ...inputFile) error {
+	return c.withMigration(request, func(request url.Values) error {
+		return c.postMultipart(method, request, response, files...)
+	})
+}
+
+func (c *Client) postMultipart(method string, request url.Values, response any, files ...inputFile) error {
 	var err error
 	var req *http.Request
 	var resp *http.Response
@@ -122,10 +163,16 @@ func (c *Client) sendRequestWithFiles(method string, request url.Values, respons
 	for _, file := range files {
 		f, err := os.Open(file.name)
 		if err != nil {
+			// fails the pending request instead of leaving it waiting for the body
+			_ = w.CloseWithError(err)
+			<-done
 			return err
 		}
 		fileWriter, err := mw.CreateFormFile(file.field, file.name)
 		if err != nil {
+			_ = f.Close()
+			_ = w.CloseWithError(err)
+			<-done
 			return err
 		}
 
@@ -158,7 +205,7 @@ func (c *Client) sendRequestWithFiles(method string, request url.Values, respons
 		}
 
 		if !apiResp.OK {
-			return fmt.Errorf(apiResp.Description)
+			return apiResp.err()
 		}
 
 		return json.Unmarshal(apiResp.Result, response)
@@ -0,0 +1,200 @@
+package tbot
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Bot ties a Client, an update source (long polling or webhook) and a
+// WorkerPool together and manages their lifecycle
+type Bot struct {
+	client          *Client
+	handler         UpdateHandler
+	poolOpts        []WorkerPoolOption
+	webhookAddr     string
+	webhookPath     string
+	shutdownTimeout time.Duration
+	hooks           []func(ctx context.Context) error
+
+	mu           sync.Mutex
+	pool         *WorkerPool
+	server       *http.Server
+	stopIntake   context.CancelFunc
+	intakeDone   chan struct{}
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// BotOption configures Bot
+type BotOption func(*Bot)
+
+// WithWorkerPool sets options of the worker pool handling updates
+func WithWorkerPool(opts ...WorkerPoolOption) BotOption {
+	return func(b *Bot) {
+		b.poolOpts = append(b.poolOpts, opts...)
+	}
+}
+
+// WithWebhook makes Bot receive updates by serving webhook requests on addr
+// and path instead of long polling. The webhook itself has to be registered
+// with Telegram separately.
+func WithWebhook(addr, path string) BotOption {
+	return func(b *Bot) {
+		b.webhookAddr = addr
+		b.webhookPath = path
+	}
+}
+
+// WithShutdownTimeout sets how long Run waits for queued and in-flight
+// updates after its context is cancelled, defaults to 30 seconds
+func WithShutdownTimeout(d time.Duration) BotOption {
+	return func(b *Bot) {
+		b.shutdownTimeout = d
+	}
+}
+
+// WithShutdownHook adds function called during shutdown after all handlers
+// returned, e.g. to flush outbound message queues. Hooks run in the order
+// they were added.
+func WithShutdownHook(fn func(ctx context.Context) error) BotOption {
+	return func(b *Bot) {
+		b.hooks = append(b.hooks, fn)
+	}
+}
+
+// NewBot creates Bot which passes updates received by client to handler
+func NewBot(client *Client, handler UpdateHandler, opts ...BotOption) *Bot {
+	b := &Bot{
+		client:          client,
+		handler:         handler,
+		webhookPath:     "/",
+		shutdownTimeout: 30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Client returns client of the bot
+func (b *Bot) Client() *Client {
+	return b.client
+}
+
+// Run receives and handles updates until ctx is cancelled or Shutdown is
+// called. When ctx is cancelled Run shuts the bot down itself, waiting up to
+// the shutdown timeout. A graceful stop returns nil.
+func (b *Bot) Run(ctx context.Context) error {
+	b.mu.Lock()
+	if b.intakeDone != nil {
+		b.mu.Unlock()
+		return errors.New("tbot: bot is already running")
+	}
+	handler := b.handler
+	if b.client.offsets != nil {
+		handler = b.client.offsets.Handler(handler)
+	}
+	opts := append([]WorkerPoolOption{WithPoolLogger(b.client.logger)}, b.poolOpts...)
+	b.pool = NewWorkerPool(handler, opts...)
+	// handlers outlive ctx, they are cancelled by shutdown only when the deadline is hit
+	b.pool.Start(context.Background())
+	intakeCtx, cancel := context.WithCancel(ctx)
+	b.stopIntake = cancel
+	b.intakeDone = make(chan struct{})
+	b.mu.Unlock()
+
+	err := b.intake(intakeCtx)
+	close(b.intakeDone)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), b.shutdownTimeout)
+	defer cancelShutdown()
+	shutdownErr := b.Shutdown(shutdownCtx)
+	if err == nil || intakeCtx.Err() != nil {
+		return shutdownErr
+	}
+	return errors.Join(err, shutdownErr)
+}
+
+// Shutdown stops receiving updates, waits until queued and in-flight updates
+// are handled, runs shutdown hooks and persists the offset of the client's
+// OffsetTracker. If ctx is done before handlers finish, their context is
+// cancelled and updates left in queues are dropped; with an OffsetTracker they
+// are delivered again after restart. The webhook server gets the same deadline,
+// pending webhook requests are dropped when it is hit.
+func (b *Bot) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	running := b.intakeDone != nil
+	b.mu.Unlock()
+	if !running {
+		return nil
+	}
+	b.shutdownOnce.Do(func() {
+		b.shutdownErr = b.shutdown(ctx)
+	})
+	return b.shutdownErr
+}
+
+func (b *Bot) shutdown(ctx context.Context) error {
+	var errs []error
+	b.stopIntake()
+	select {
+	case <-b.intakeDone:
+	case <-ctx.Done():
+	}
+	b.mu.Lock()
+	server := b.server
+	b.mu.Unlock()
+	if server != nil {
+		// waits for webhook requests blocked on a full pool, the pool keeps draining meanwhile
+		if err := server.Shutdown(ctx); err != nil {
+			// handlers hang, drop the pending requests so Telegram delivers them again
+			_ = server.Close()
+		}
+	}
+	if err := b.pool.Stop(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	for _, hook := range b.hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if b.client.offsets != nil {
+		if err := b.client.offsets.Flush(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *Bot) intake(ctx context.Context) error {
+	if b.webhookAddr == "" {
+		return b.client.Poll(ctx, b.pool.Submit)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(b.webhookPath, b.client.WebhookHandler(b.pool.Submit))
+	listener, err := net.Listen("tcp", b.webhookAddr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: mux}
+	b.mu.Lock()
+	b.server = server
+	b.mu.Unlock()
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+		// the server is stopped by shutdown within its deadline
+		return ctx.Err()
+	}
+}
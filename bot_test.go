@@ -0,0 +1,140 @@
+package tbot
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBot_ShutdownDrainsAndPersistsOffset(t *testing.T) {
+	var served int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if atomic.AddInt32(&served, 1) > 1 || r.Form.Get("offset") != "" {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":[]}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":[
+			{"update_id":7,"message":{"message_id":1,"chat":{"id":1}}},
+			{"update_id":8,"message":{"message_id":2,"chat":{"id":2}}}]}`)
+	}))
+	defer srv.Close()
+
+	storage := NewMemoryStorage()
+	tracker, _ := NewOffsetTracker(storage)
+	client := NewClient("token", srv.URL, WithPollTimeout(0), WithOffsetTracker(tracker))
+
+	var handled int32
+	bot := NewBot(client, func(ctx context.Context, u *Update) error {
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&handled, 1)
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Run(ctx) }()
+
+	for atomic.LoadInt32(&served) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&handled); got != 2 {
+		t.Errorf("handled %d updates, want 2", got)
+	}
+	value, _ := storage.Get("tbot:offset")
+	if string(value) != "9" {
+		t.Errorf("persisted offset = %q, want %q", value, "9")
+	}
+}
+
+func TestBot_WebhookShutdownDeadline(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		stop    func(bot *Bot, cancel context.CancelFunc)
+	}{
+		{
+			// the server and the pool share one shutdown timeout
+			name:    "run context cancelled",
+			timeout: 200 * time.Millisecond,
+			stop: func(bot *Bot, cancel context.CancelFunc) {
+				cancel()
+			},
+		},
+		{
+			name:    "shutdown deadline",
+			timeout: time.Hour,
+			stop: func(bot *Bot, cancel context.CancelFunc) {
+				ctx, cancelShutdown := context.WithTimeout(context.Background(), 200*time.Millisecond)
+				defer cancelShutdown()
+				_ = bot.Shutdown(ctx)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("net.Listen() error = %v", err)
+			}
+			addr := l.Addr().String()
+			_ = l.Close()
+
+			hang := make(chan struct{})
+			defer close(hang)
+			started := make(chan struct{}, 1)
+			bot := NewBot(NewClient("token", "http://localhost"), func(ctx context.Context, u *Update) error {
+				started <- struct{}{}
+				<-hang
+				return nil
+			}, WithWebhook(addr, "/"), WithShutdownTimeout(tt.timeout), WithWorkerPool(WithPoolSize(1), WithQueueSize(0)))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := make(chan error)
+			go func() { done <- bot.Run(ctx) }()
+
+			post := func(id int) {
+				for i := 0; i < 50; i++ {
+					resp, err := http.Post("http://"+addr+"/", "application/json", strings.NewReader(fmt.Sprintf(`{"update_id":%d}`, id)))
+					if err == nil {
+						_ = resp.Body.Close()
+						return
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}
+			go post(1)
+			<-started
+			// blocks in Submit, the only worker hangs
+			go post(2)
+			// a request whose body never arrives keeps the server busy
+			stalled, err := net.Dial("tcp", addr)
+			if err != nil {
+				t.Fatalf("net.Dial() error = %v", err)
+			}
+			defer stalled.Close()
+			_, _ = fmt.Fprint(stalled, "POST / HTTP/1.1\r\nHost: bot\r\nContent-Length: 100\r\n\r\n{")
+			time.Sleep(20 * time.Millisecond)
+
+			start := time.Now()
+			go tt.stop(bot, cancel)
+			select {
+			case <-done:
+				if elapsed := time.Since(start); elapsed > 350*time.Millisecond {
+					t.Errorf("Run() returned after %v, want one shutdown deadline of 200ms", elapsed)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatal("Run() did not return after the shutdown deadline")
+			}
+		})
+	}
+}
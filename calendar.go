@@ -0,0 +1,310 @@
+package tbot
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// CalendarLocale holds names used by Calendar
+type CalendarLocale struct {
+	// Weekdays are short names starting from Sunday
+	Weekdays [7]string
+	Months   [12]string
+	// Unavailable is shown when a time outside of the date range is picked
+	Unavailable string
+}
+
+var (
+	CalendarLocaleEN = CalendarLocale{
+		Weekdays: [7]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+		Months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		Unavailable: "This time is not available",
+	}
+	CalendarLocaleID = CalendarLocale{
+		Weekdays: [7]string{"Min", "Sen", "Sel", "Rab", "Kam", "Jum", "Sab"},
+		Months: [12]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+			"Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+		Unavailable: "Waktu ini tidak tersedia",
+	}
+)
+
+// Calendar is an inline keyboard date (and optionally time) picker. It shows
+// a month grid, navigates months with ‹ › and years with « », and edits the
+// keyboard in place. Callback data of its buttons starts with "<name>:", so
+// name must be unique among callback routes.
+type Calendar struct {
+	name       string
+	locale     CalendarLocale
+	weekStart  time.Weekday
+	location   *time.Location
+	min, max   time.Time
+	withTime   bool
+	minuteStep int
+	onSelect   func(c *Context, t time.Time) error
+}
+
+// CalendarOption configures Calendar
+type CalendarOption func(*Calendar)
+
+// WithCalendarLocale sets weekday and month names, defaults to CalendarLocaleEN
+func WithCalendarLocale(locale CalendarLocale) CalendarOption {
+	return func(cal *Calendar) {
+		cal.locale = locale
+	}
+}
+
+// WithWeekStart sets the first day of the week, defaults to Monday
+func WithWeekStart(day time.Weekday) CalendarOption {
+	return func(cal *Calendar) {
+		cal.weekStart = day
+	}
+}
+
+// WithCalendarLocation sets time zone of selected times, defaults to UTC
+func WithCalendarLocation(loc *time.Location) CalendarOption {
+	return func(cal *Calendar) {
+		cal.location = loc
+	}
+}
+
+// WithDateRange limits selectable dates, zero time means no limit
+func WithDateRange(min, max time.Time) CalendarOption {
+	return func(cal *Calendar) {
+		cal.min = min
+		cal.max = max
+	}
+}
+
+// WithTimeSelection asks for hour and minute after the date is picked,
+// minutes are offered with minuteStep granularity
+func WithTimeSelection(minuteStep int) CalendarOption {
+	return func(cal *Calendar) {
+		cal.withTime = true
+		cal.minuteStep = minuteStep
+	}
+}
+
+// NewCalendar creates Calendar calling onSelect with the picked time
+func NewCalendar(name string, onSelect func(c *Context, t time.Time) error, opts ...CalendarOption) *Calendar {
+	cal := &Calendar{
+		name:       name,
+		locale:     CalendarLocaleEN,
+		weekStart:  time.Monday,
+		location:   time.UTC,
+		minuteStep: 15,
+		onSelect:   onSelect,
+	}
+	for _, opt := range opts {
+		opt(cal)
+	}
+	if cal.minuteStep < 1 || cal.minuteStep > 60 {
+		cal.minuteStep = 15
+	}
+	return cal
+}
+
+// Register adds callback routes of the calendar to router
+func (cal *Calendar) Register(r *Router) {
+	r.OnCallback(cal.name+":m:{month}", cal.navigate)
+	r.OnCallback(cal.name+":d:{date}", cal.pickDate)
+	r.OnCallback(cal.name+":h:{date}:{hour}", cal.pickHour)
+	r.OnCallback(cal.name+":t:{date}:{hour}:{minute}", cal.pickTime)
+	r.OnCallback(cal.name+":n", func(c *Context) error { return nil })
+}
+
+// Send sends text with the calendar opened at month of t
+func (cal *Calendar) Send(c *Context, chatID string, text string, t time.Time, opts ...sendOption) (*Message, error) {
+	markup, err := cal.Month(t)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, OptInlineKeyboardMarkup(markup))
+	return c.Client.SendMessage(chatID, "", text, opts...)
+}
+
+// Month renders month grid of the month containing t
+func (cal *Calendar) Month(t time.Time) (*InlineKeyboardMarkup, error) {
+	t = t.In(cal.location)
+	first := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, cal.location)
+	prevMonth, nextMonth := first.AddDate(0, -1, 0), first.AddDate(0, 1, 0)
+	prevYear, nextYear := first.AddDate(-1, 0, 0), first.AddDate(1, 0, 0)
+
+	kb := NewInlineKeyboard()
+	kb.Add(
+		cal.navButton("«", prevYear),
+		cal.navButton("‹", prevMonth),
+		cal.noop(fmt.Sprintf("%s %d", cal.locale.Months[first.Month()-1], first.Year())),
+		cal.navButton("›", nextMonth),
+		cal.navButton("»", nextYear),
+	)
+
+	kb.Row()
+	for i := 0; i < 7; i++ {
+		kb.Add(cal.noop(cal.locale.Weekdays[(int(cal.weekStart)+i)%7]))
+	}
+
+	kb.Row().Columns(7)
+	lead := (int(first.Weekday()) - int(cal.weekStart) + 7) % 7
+	for i := 0; i < lead; i++ {
+		kb.Add(cal.noop(" "))
+	}
+	days := 0
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		if cal.dayAllowed(day) {
+			kb.Add(InlineCallback(strconv.Itoa(day.Day()), cal.name+":d:"+day.Format("20060102")))
+		} else {
+			kb.Add(cal.noop("·"))
+		}
+		days++
+	}
+	for (lead+days)%7 != 0 {
+		kb.Add(cal.noop(" "))
+		days++
+	}
+	return kb.Build()
+}
+
+// navButton links to month starting at target if any of its days is
+// selectable, otherwise renders an inactive button
+func (cal *Calendar) navButton(text string, target time.Time) InlineKeyboardButton {
+	end := target.AddDate(0, 1, 0)
+	if (!cal.max.IsZero() && target.After(cal.max)) || (!cal.min.IsZero() && !end.After(cal.min)) {
+		return cal.noop(" ")
+	}
+	return InlineCallback(text, cal.name+":m:"+target.Format("200601"))
+}
+
+func (cal *Calendar) noop(text string) InlineKeyboardButton {
+	return InlineCallback(text, cal.name+":n")
+}
+
+func (cal *Calendar) dayAllowed(day time.Time) bool {
+	end := day.AddDate(0, 0, 1)
+	if !cal.min.IsZero() && !end.After(cal.min) {
+		return false
+	}
+	if !cal.max.IsZero() && day.After(cal.max) {
+		return false
+	}
+	return true
+}
+
+func (cal *Calendar) timeAllowed(date time.Time, hour, minute int) bool {
+	t := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, cal.location)
+	return (cal.min.IsZero() || !t.Before(cal.min)) && (cal.max.IsZero() || !t.After(cal.max))
+}
+
+// hourAllowed tells whether any minute offered in hour is within the range
+func (cal *Calendar) hourAllowed(date time.Time, hour int) bool {
+	for m := 0; m < 60; m += cal.minuteStep {
+		if cal.timeAllowed(date, hour, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// unavailable tells the user the picked time is out of the range
+func (cal *Calendar) unavailable(c *Context) error {
+	if cal.locale.Unavailable == "" {
+		return c.AnswerCallback()
+	}
+	return c.AnswerCallback(OptCallbackText(cal.locale.Unavailable))
+}
+
+func (cal *Calendar) hours(date time.Time) (*InlineKeyboardMarkup, error) {
+	kb := NewInlineKeyboard().Add(cal.noop(date.Format("2006-01-02"))).Row().Columns(6)
+	for h := 0; h < 24; h++ {
+		if !cal.hourAllowed(date, h) {
+			kb.Add(cal.noop("·"))
+			continue
+		}
+		kb.Add(InlineCallback(fmt.Sprintf("%02d", h), fmt.Sprintf("%s:h:%s:%d", cal.name, date.Format("20060102"), h)))
+	}
+	kb.Columns(0).Row().Add(InlineCallback("‹", cal.name+":m:"+date.Format("200601")))
+	return kb.Build()
+}
+
+func (cal *Calendar) minutes(date time.Time, hour int) (*InlineKeyboardMarkup, error) {
+	day := date.Format("20060102")
+	kb := NewInlineKeyboard().Add(cal.noop(fmt.Sprintf("%s %02d:--", date.Format("2006-01-02"), hour))).Row().Columns(4)
+	for m := 0; m < 60; m += cal.minuteStep {
+		if !cal.timeAllowed(date, hour, m) {
+			kb.Add(cal.noop("·"))
+			continue
+		}
+		kb.Add(InlineCallback(fmt.Sprintf("%02d:%02d", hour, m), fmt.Sprintf("%s:t:%s:%d:%d", cal.name, day, hour, m)))
+	}
+	kb.Columns(0).Row().Add(InlineCallback("‹", cal.name+":d:"+day))
+	return kb.Build()
+}
+
+func (cal *Calendar) navigate(c *Context) error {
+	month, err := time.ParseInLocation("200601", c.Param("month"), cal.location)
+	if err != nil {
+		return err
+	}
+	markup, err := cal.Month(month)
+	if err != nil {
+		return err
+	}
+	return c.EditMarkup(markup)
+}
+
+func (cal *Calendar) pickDate(c *Context) error {
+	date, err := time.ParseInLocation("20060102", c.Param("date"), cal.location)
+	if err != nil {
+		return err
+	}
+	if !cal.dayAllowed(date) {
+		return cal.unavailable(c)
+	}
+	if !cal.withTime {
+		return cal.onSelect(c, date)
+	}
+	markup, err := cal.hours(date)
+	if err != nil {
+		return err
+	}
+	return c.EditMarkup(markup)
+}
+
+func (cal *Calendar) pickHour(c *Context) error {
+	date, err := time.ParseInLocation("20060102", c.Param("date"), cal.location)
+	if err != nil {
+		return err
+	}
+	hour, err := strconv.Atoi(c.Param("hour"))
+	if err != nil {
+		return err
+	}
+	if !cal.hourAllowed(date, hour) {
+		return cal.unavailable(c)
+	}
+	markup, err := cal.minutes(date, hour)
+	if err != nil {
+		return err
+	}
+	return c.EditMarkup(markup)
+}
+
+func (cal *Calendar) pickTime(c *Context) error {
+	date, err := time.ParseInLocation("20060102", c.Param("date"), cal.location)
+	if err != nil {
+		return err
+	}
+	hour, err := strconv.Atoi(c.Param("hour"))
+	if err != nil {
+		return err
+	}
+	minute, err := strconv.Atoi(c.Param("minute"))
+	if err != nil {
+		return err
+	}
+	if !cal.timeAllowed(date, hour, minute) {
+		return cal.unavailable(c)
+	}
+	return cal.onSelect(c, time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, cal.location))
+}
@@ -0,0 +1,144 @@
+package tbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCalendar_Month(t *testing.T) {
+	cal := NewCalendar("cal", nil, WithDateRange(
+		time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 20, 0, 0, 0, 0, time.UTC),
+	))
+	markup, err := cal.Month(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Month() error = %v", err)
+	}
+	rows := markup.InlineKeyboard
+	if want := []string{" ", " ", "March 2024", "›", " "}; !reflect.DeepEqual(buttonTexts(rows[0]), want) {
+		t.Errorf("header = %q, want %q", buttonTexts(rows[0]), want)
+	}
+	if want := []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"}; !reflect.DeepEqual(buttonTexts(rows[1]), want) {
+		t.Errorf("weekdays = %q, want %q", buttonTexts(rows[1]), want)
+	}
+	// March 1st 2024 is a Friday
+	if want := []string{" ", " ", " ", " ", "·", "·", "·"}; !reflect.DeepEqual(buttonTexts(rows[2]), want) {
+		t.Errorf("first week = %q, want %q", buttonTexts(rows[2]), want)
+	}
+	if want := []string{"·", "·", "·", "·", "·", "·", "10"}; !reflect.DeepEqual(buttonTexts(rows[3]), want) {
+		t.Errorf("second week = %q, want %q", buttonTexts(rows[3]), want)
+	}
+	if got := rows[3][6].CallbackData; got != "cal:d:20240310" {
+		t.Errorf("callback data of March 10 = %q", got)
+	}
+	if len(rows) != 7 {
+		t.Errorf("rows = %d, want 7", len(rows))
+	}
+}
+
+func TestCalendar_TimeBounds(t *testing.T) {
+	min := time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC)
+	max := time.Date(2024, 3, 12, 17, 0, 0, 0, time.UTC)
+	cal := NewCalendar("cal", nil, WithDateRange(min, max), WithTimeSelection(15))
+
+	tests := []struct {
+		name   string
+		render func() (*InlineKeyboardMarkup, error)
+		row    int
+		want   []string
+	}{
+		{
+			name:   "hours of the first day",
+			render: func() (*InlineKeyboardMarkup, error) { return cal.hours(min) },
+			row:    2,
+			want:   []string{"·", "·", "·", "09", "10", "11"},
+		},
+		{
+			name:   "hours of the last day",
+			render: func() (*InlineKeyboardMarkup, error) { return cal.hours(max) },
+			row:    4,
+			want:   []string{"·", "·", "·", "·", "·", "·"},
+		},
+		{
+			name:   "minutes of the first hour",
+			render: func() (*InlineKeyboardMarkup, error) { return cal.minutes(min, 9) },
+			row:    1,
+			want:   []string{"·", "·", "09:30", "09:45"},
+		},
+		{
+			name:   "minutes of the last hour",
+			render: func() (*InlineKeyboardMarkup, error) { return cal.minutes(max, 17) },
+			row:    1,
+			want:   []string{"17:00", "·", "·", "·"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markup, err := tt.render()
+			if err != nil {
+				t.Fatalf("render error = %v", err)
+			}
+			if got := buttonTexts(markup.InlineKeyboard[tt.row]); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("row %d = %q, want %q", tt.row, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendar_Callbacks(t *testing.T) {
+	var calls []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		r.Form.Set("method", r.URL.Path)
+		calls = append(calls, r.Form)
+		if r.URL.Path == "/bottoken/answerCallbackQuery" {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":3}}`)
+	}))
+	defer srv.Close()
+
+	var picked []time.Time
+	cal := NewCalendar("cal", func(c *Context, t time.Time) error {
+		picked = append(picked, t)
+		return nil
+	}, WithDateRange(time.Date(2024, 3, 10, 9, 30, 0, 0, time.UTC), time.Time{}), WithTimeSelection(15))
+	router := NewRouter(NewClient("token", srv.URL))
+	cal.Register(router)
+
+	tests := []struct {
+		data   string
+		method string
+		text   string
+	}{
+		{data: "cal:m:202404", method: "/bottoken/editMessageReplyMarkup"},
+		{data: "cal:d:20240309", method: "/bottoken/answerCallbackQuery", text: "This time is not available"},
+		{data: "cal:d:20240310", method: "/bottoken/editMessageReplyMarkup"},
+		{data: "cal:h:20240310:8", method: "/bottoken/answerCallbackQuery", text: "This time is not available"},
+		{data: "cal:h:20240310:9", method: "/bottoken/editMessageReplyMarkup"},
+		{data: "cal:t:20240310:9:15", method: "/bottoken/answerCallbackQuery", text: "This time is not available"},
+		{data: "cal:t:20240310:9:45", method: "/bottoken/answerCallbackQuery"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			calls = nil
+			u := &Update{CallbackQuery: &CallbackQuery{ID: "q", Data: tt.data, Message: &Message{MessageID: 3, Chat: Chat{ID: 1}}}}
+			if err := router.HandleUpdate(context.Background(), u); err != nil {
+				t.Fatalf("HandleUpdate() error = %v", err)
+			}
+			if len(calls) == 0 || calls[0].Get("method") != tt.method || calls[0].Get("text") != tt.text {
+				t.Errorf("first request = %v, want %s with text %q", calls, tt.method, tt.text)
+			}
+		})
+	}
+	if want := []time.Time{time.Date(2024, 3, 10, 9, 45, 0, 0, time.UTC)}; !reflect.DeepEqual(picked, want) {
+		t.Errorf("picked = %v, want %v", picked, want)
+	}
+}
@@ -0,0 +1,41 @@
+package tbot
+
+import (
+	"net/url"
+	"strconv"
+)
+
+var (
+	OptCallbackText = func(text string) sendOption {
+		return func(r url.Values) {
+			r.Set("text", text)
+		}
+	}
+	OptCallbackURL = func(u string) sendOption {
+		return func(r url.Values) {
+			r.Set("url", u)
+		}
+	}
+	OptShowAlert = func(r url.Values) { r.Set("show_alert", "true") }
+	OptCacheTime = func(seconds int) sendOption {
+		return func(r url.Values) {
+			r.Set("cache_time", strconv.Itoa(seconds))
+		}
+	}
+)
+
+// AnswerCallbackQuery sends answer to callback query sent from inline keyboard.
+// Available options:
+//   - OptCallbackText(text string)
+//   - OptShowAlert
+//   - OptCallbackURL(url string)
+//   - OptCacheTime(seconds int)
+func (c *Client) AnswerCallbackQuery(callbackQueryID string, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("callback_query_id", callbackQueryID)
+	for _, opt := range opts {
+		opt(req)
+	}
+	var answered bool
+	return c.sendRequest("/answerCallbackQuery", req, &answered)
+}
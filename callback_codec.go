@@ -0,0 +1,279 @@
+package tbot
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxCallbackDataLength is the limit of callback_data in bytes
+const MaxCallbackDataLength = 64
+
+var (
+	ErrCallbackTooLong   = errors.New("tbot: callback data exceeds 64 bytes")
+	ErrCallbackVersion   = errors.New("tbot: callback data version mismatch")
+	ErrCallbackSignature = errors.New("tbot: callback data signature is invalid")
+	ErrCallbackExpired   = errors.New("tbot: callback data is expired")
+	ErrCallbackMalformed = errors.New("tbot: callback data is malformed")
+)
+
+const (
+	callbackSignatureSize = 6
+	callbackStoredMarker  = "~"
+)
+
+// CallbackCodec encodes structs into callback_data and back. Exported fields
+// of string, bool, integer and float kinds are written in declaration order,
+// integers in base 36, so a struct like {Action: "del", ID: 123456, Page: 3}
+// becomes "1.del,2n9c,3". Fields tagged `cb:"-"` are skipped.
+//
+// The payload starts with the codec version, so data of buttons sent by an
+// older release is rejected with ErrCallbackVersion instead of being decoded
+// into wrong fields. With a secret the payload is signed with truncated
+// HMAC-SHA256. When the encoded data does not fit into 64 bytes and storage is
+// set, the payload is kept in storage and the button carries a short key.
+type CallbackCodec struct {
+	version int
+	secret  []byte
+	storage Storage
+	ttl     time.Duration
+}
+
+// CallbackCodecOption configures CallbackCodec
+type CallbackCodecOption func(*CallbackCodec)
+
+// WithCodecVersion sets version written to and expected in payloads, defaults to 1
+func WithCodecVersion(version int) CallbackCodecOption {
+	return func(c *CallbackCodec) {
+		c.version = version
+	}
+}
+
+// WithCodecSecret enables signing of payloads with secret
+func WithCodecSecret(secret []byte) CallbackCodecOption {
+	return func(c *CallbackCodec) {
+		c.secret = secret
+	}
+}
+
+// WithCodecStorage enables keeping payloads longer than the limit in storage
+// for ttl. Zero ttl keeps them forever.
+func WithCodecStorage(storage Storage, ttl time.Duration) CallbackCodecOption {
+	return func(c *CallbackCodec) {
+		c.storage = storage
+		c.ttl = ttl
+	}
+}
+
+// NewCallbackCodec creates CallbackCodec
+func NewCallbackCodec(opts ...CallbackCodecOption) *CallbackCodec {
+	c := &CallbackCodec{version: 1}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Encode returns callback data "<prefix>:<payload>" for struct v. Handlers are
+// registered with Router.OnCallback(prefix+":*", ...) and decode c.Param("*")
+// with the same prefix. The signature covers the prefix, so a signed payload
+// is not accepted under another prefix.
+func (c *CallbackCodec) Encode(prefix string, v any) (string, error) {
+	fields, err := encodeCallbackFields(v)
+	if err != nil {
+		return "", err
+	}
+	payload := strconv.FormatInt(int64(c.version), 36) + "." + fields
+	if c.secret != nil {
+		payload += "." + c.sign(prefix, payload)
+	}
+	data := prefix + ":" + payload
+	if len(data) <= MaxCallbackDataLength {
+		return data, nil
+	}
+	if c.storage == nil {
+		return "", ErrCallbackTooLong
+	}
+	key, err := randomCallbackKey()
+	if err != nil {
+		return "", err
+	}
+	if err := c.storage.Set("tbot:callback:"+key, []byte(payload), c.ttl); err != nil {
+		return "", fmt.Errorf("unable to store callback data: %v", err)
+	}
+	data = prefix + ":" + callbackStoredMarker + key
+	if len(data) > MaxCallbackDataLength {
+		return "", ErrCallbackTooLong
+	}
+	return data, nil
+}
+
+// Decode decodes payload produced by Encode with prefix (the data without
+// "<prefix>:") into the struct pointed to by v
+func (c *CallbackCodec) Decode(prefix, payload string, v any) error {
+	if strings.HasPrefix(payload, callbackStoredMarker) {
+		if c.storage == nil {
+			return ErrCallbackExpired
+		}
+		stored, err := c.storage.Get("tbot:callback:" + strings.TrimPrefix(payload, callbackStoredMarker))
+		if errors.Is(err, ErrNotFound) {
+			return ErrCallbackExpired
+		}
+		if err != nil {
+			return fmt.Errorf("unable to load callback data: %v", err)
+		}
+		payload = string(stored)
+	}
+
+	parts := strings.Split(payload, ".")
+	if c.secret != nil {
+		if len(parts) != 3 {
+			return ErrCallbackSignature
+		}
+		signed := parts[0] + "." + parts[1]
+		if !hmac.Equal([]byte(parts[2]), []byte(c.sign(prefix, signed))) {
+			return ErrCallbackSignature
+		}
+		parts = parts[:2]
+	}
+	if len(parts) != 2 {
+		return ErrCallbackMalformed
+	}
+	version, err := strconv.ParseInt(parts[0], 36, 64)
+	if err != nil {
+		return ErrCallbackMalformed
+	}
+	if int(version) != c.version {
+		return ErrCallbackVersion
+	}
+	return decodeCallbackFields(parts[1], v)
+}
+
+func (c *CallbackCodec) sign(prefix, payload string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(prefix + ":" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:callbackSignatureSize])
+}
+
+func randomCallbackKey() (string, error) {
+	b := make([]byte, 9)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+var callbackEscaper = strings.NewReplacer("%", "%25", ",", "%2C", ".", "%2E")
+var callbackUnescaper = strings.NewReplacer("%25", "%", "%2C", ",", "%2E", ".")
+
+func callbackStruct(v reflect.Value) (reflect.Value, error) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return v, fmt.Errorf("tbot: callback value is nil")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return v, fmt.Errorf("tbot: callback value must be a struct, got %s", v.Kind())
+	}
+	return v, nil
+}
+
+func callbackFields(v reflect.Value) []reflect.Value {
+	var fields []reflect.Value
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Tag.Get("cb") == "-" {
+			continue
+		}
+		fields = append(fields, v.Field(i))
+	}
+	return fields
+}
+
+func encodeCallbackFields(v any) (string, error) {
+	rv, err := callbackStruct(reflect.ValueOf(v))
+	if err != nil {
+		return "", err
+	}
+	var values []string
+	for _, f := range callbackFields(rv) {
+		switch f.Kind() {
+		case reflect.String:
+			values = append(values, callbackEscaper.Replace(f.String()))
+		case reflect.Bool:
+			if f.Bool() {
+				values = append(values, "1")
+			} else {
+				values = append(values, "")
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			values = append(values, strconv.FormatInt(f.Int(), 36))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			values = append(values, strconv.FormatUint(f.Uint(), 36))
+		case reflect.Float32, reflect.Float64:
+			// escaped, the decimal point is the payload separator
+			values = append(values, callbackEscaper.Replace(strconv.FormatFloat(f.Float(), 'g', -1, 64)))
+		default:
+			return "", fmt.Errorf("tbot: unsupported callback field kind %s", f.Kind())
+		}
+	}
+	return strings.Join(values, ","), nil
+}
+
+func decodeCallbackFields(s string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return fmt.Errorf("tbot: callback value must be a pointer to struct")
+	}
+	rv, err := callbackStruct(rv)
+	if err != nil {
+		return err
+	}
+	fields := callbackFields(rv)
+	values := strings.Split(s, ",")
+	if len(fields) == 0 && s == "" {
+		return nil
+	}
+	if len(values) != len(fields) {
+		return ErrCallbackMalformed
+	}
+	for i, f := range fields {
+		value := values[i]
+		switch f.Kind() {
+		case reflect.String:
+			f.SetString(callbackUnescaper.Replace(value))
+		case reflect.Bool:
+			f.SetBool(value != "")
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(value, 36, f.Type().Bits())
+			if err != nil {
+				return ErrCallbackMalformed
+			}
+			f.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(value, 36, f.Type().Bits())
+			if err != nil {
+				return ErrCallbackMalformed
+			}
+			f.SetUint(n)
+		case reflect.Float32, reflect.Float64:
+			n, err := strconv.ParseFloat(callbackUnescaper.Replace(value), f.Type().Bits())
+			if err != nil {
+				return ErrCallbackMalformed
+			}
+			f.SetFloat(n)
+		default:
+			return fmt.Errorf("tbot: unsupported callback field kind %s", f.Kind())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package tbot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCallback struct {
+	Action string
+	ID     int64
+	Page   uint
+	Hidden bool
+	Ratio  float64
+	skip   int
+}
+
+func TestCallbackCodec_RoundTrip(t *testing.T) {
+	in := testCallback{Action: "del,a.b%", ID: 123456, Page: 3, Hidden: true, Ratio: -1.5e-7}
+	tests := []struct {
+		name  string
+		codec *CallbackCodec
+	}{
+		{name: "plain", codec: NewCallbackCodec()},
+		{name: "signed", codec: NewCallbackCodec(WithCodecSecret([]byte("secret")))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.codec.Encode("inc", in)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			var out testCallback
+			if err := tt.codec.Decode("inc", strings.TrimPrefix(data, "inc:"), &out); err != nil {
+				t.Fatalf("Decode(%q) error = %v", data, err)
+			}
+			if out != in {
+				t.Errorf("Decode() = %+v, want %+v", out, in)
+			}
+		})
+	}
+}
+
+func TestCallbackCodec_Float(t *testing.T) {
+	codec := NewCallbackCodec()
+	data, err := codec.Encode("p", struct{ X float64 }{1.5})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	var out struct{ X float64 }
+	if err := codec.Decode("p", strings.TrimPrefix(data, "p:"), &out); err != nil || out.X != 1.5 {
+		t.Errorf("Decode(%q) = %v, %v, want 1.5", data, out.X, err)
+	}
+}
+
+func TestCallbackCodec_Compact(t *testing.T) {
+	data, _ := NewCallbackCodec().Encode("inc", struct {
+		Action string
+		ID     int
+		Page   int
+	}{"del", 123456, 3})
+	if want := "inc:1.del,2n9c,3"; data != want {
+		t.Errorf("Encode() = %q, want %q", data, want)
+	}
+}
+
+func TestCallbackCodec_Rejects(t *testing.T) {
+	signed := NewCallbackCodec(WithCodecSecret([]byte("secret")))
+	data, _ := signed.Encode("inc", testCallback{ID: 1})
+	tampered := strings.Replace(strings.TrimPrefix(data, "inc:"), ",1,", ",2,", 1)
+	var out testCallback
+	if err := signed.Decode("inc", tampered, &out); err != ErrCallbackSignature {
+		t.Errorf("Decode(tampered) error = %v, want %v", err, ErrCallbackSignature)
+	}
+
+	data, _ = signed.Encode("view", testCallback{ID: 1})
+	if err := signed.Decode("del", strings.TrimPrefix(data, "view:"), &out); err != ErrCallbackSignature {
+		t.Errorf("Decode(other prefix) error = %v, want %v", err, ErrCallbackSignature)
+	}
+
+	data, _ = NewCallbackCodec(WithCodecVersion(2)).Encode("inc", testCallback{ID: 1})
+	if err := NewCallbackCodec().Decode("inc", strings.TrimPrefix(data, "inc:"), &out); err != ErrCallbackVersion {
+		t.Errorf("Decode(old version) error = %v, want %v", err, ErrCallbackVersion)
+	}
+
+	long := testCallback{Action: strings.Repeat("x", 80)}
+	if _, err := NewCallbackCodec().Encode("inc", long); err != ErrCallbackTooLong {
+		t.Errorf("Encode(long) error = %v, want %v", err, ErrCallbackTooLong)
+	}
+}
+
+func TestCallbackCodec_StorageFallback(t *testing.T) {
+	codec := NewCallbackCodec(WithCodecSecret([]byte("secret")), WithCodecStorage(NewMemoryStorage(), time.Hour))
+	in := testCallback{Action: strings.Repeat("x", 80), ID: 42}
+	data, err := codec.Encode("inc", in)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if len(data) > MaxCallbackDataLength {
+		t.Fatalf("Encode() = %q is longer than the limit", data)
+	}
+	var out testCallback
+	if err := codec.Decode("inc", strings.TrimPrefix(data, "inc:"), &out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if out != in {
+		t.Errorf("Decode() = %+v, want %+v", out, in)
+	}
+	if err := codec.Decode("inc", "~missing", &out); err != ErrCallbackExpired {
+		t.Errorf("Decode(missing) error = %v, want %v", err, ErrCallbackExpired)
+	}
+}
@@ -0,0 +1,305 @@
+package tbot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Statuses of chat members
+const (
+	ChatMemberStatusOwner         = "creator"
+	ChatMemberStatusAdministrator = "administrator"
+	ChatMemberStatusMember        = "member"
+	ChatMemberStatusRestricted    = "restricted"
+	ChatMemberStatusLeft          = "left"
+	ChatMemberStatusBanned        = "kicked"
+)
+
+// ChatMember is a member of a chat, one of ChatMemberOwner,
+// ChatMemberAdministrator, ChatMemberMember, ChatMemberRestricted,
+// ChatMemberLeft and ChatMemberBanned, or ChatMemberUnknown for a status
+// unknown to this package. Use a type switch to get fields
+// specific to the status.
+type ChatMember interface {
+	// MemberStatus returns one of ChatMemberStatus constants
+	MemberStatus() string
+	// MemberUser returns the user the membership is of
+	MemberUser() *User
+}
+
+// ChatMemberOwner is the owner of the chat
+type ChatMemberOwner struct {
+	User        *User  `json:"user"`
+	IsAnonymous bool   `json:"is_anonymous"`
+	CustomTitle string `json:"custom_title,omitempty"`
+}
+
+// ChatMemberAdministrator is an administrator of the chat
+type ChatMemberAdministrator struct {
+	ChatAdministratorRights
+	User *User `json:"user"`
+	// CanBeEdited True, if the bot is allowed to edit administrator privileges of that user
+	CanBeEdited bool   `json:"can_be_edited"`
+	CustomTitle string `json:"custom_title,omitempty"`
+}
+
+// ChatMemberMember is a member without additional privileges or restrictions
+type ChatMemberMember struct {
+	User *User `json:"user"`
+	// UntilDate is unix time when the user's subscription expires, 0 if it does not
+	UntilDate int64 `json:"until_date,omitempty"`
+}
+
+// ChatMemberRestricted is a user under restrictions in the chat, supergroups only
+type ChatMemberRestricted struct {
+	ChatPermissions
+	User *User `json:"user"`
+	// IsMember True, if the user is a member of the chat at the moment of the request
+	IsMember bool `json:"is_member"`
+	// UntilDate is unix time when restrictions will be lifted, 0 if they are forever
+	UntilDate int64 `json:"until_date"`
+}
+
+// ChatMemberLeft is a user who isn't currently a member of the chat, but may join it themselves
+type ChatMemberLeft struct {
+	User *User `json:"user"`
+}
+
+// ChatMemberBanned is a user who was banned in the chat and can't return to it or view its messages
+type ChatMemberBanned struct {
+	User *User `json:"user"`
+	// UntilDate is unix time when the ban will be lifted, 0 if it is forever
+	UntilDate int64 `json:"until_date"`
+}
+
+func (m *ChatMemberOwner) MemberStatus() string         { return ChatMemberStatusOwner }
+func (m *ChatMemberAdministrator) MemberStatus() string { return ChatMemberStatusAdministrator }
+func (m *ChatMemberMember) MemberStatus() string        { return ChatMemberStatusMember }
+func (m *ChatMemberRestricted) MemberStatus() string    { return ChatMemberStatusRestricted }
+func (m *ChatMemberLeft) MemberStatus() string          { return ChatMemberStatusLeft }
+func (m *ChatMemberBanned) MemberStatus() string        { return ChatMemberStatusBanned }
+
+func (m *ChatMemberOwner) MemberUser() *User         { return m.User }
+func (m *ChatMemberAdministrator) MemberUser() *User { return m.User }
+func (m *ChatMemberMember) MemberUser() *User        { return m.User }
+func (m *ChatMemberRestricted) MemberUser() *User    { return m.User }
+func (m *ChatMemberLeft) MemberUser() *User          { return m.User }
+func (m *ChatMemberBanned) MemberUser() *User        { return m.User }
+
+// ChatMemberUnknown is a member with a status this package does not know
+type ChatMemberUnknown struct {
+	Status string `json:"status"`
+	User   *User  `json:"user"`
+}
+
+func (m *ChatMemberUnknown) MemberStatus() string { return m.Status }
+func (m *ChatMemberUnknown) MemberUser() *User    { return m.User }
+
+// unmarshalChatMember decodes ChatMember variant selected by its status,
+// JSON null is decoded as nil
+func unmarshalChatMember(data []byte) (ChatMember, error) {
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+	var head struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(data, &head); err != nil {
+		return nil, err
+	}
+	var m ChatMember
+	switch head.Status {
+	case ChatMemberStatusOwner:
+		m = &ChatMemberOwner{}
+	case ChatMemberStatusAdministrator:
+		m = &ChatMemberAdministrator{}
+	case ChatMemberStatusMember:
+		m = &ChatMemberMember{}
+	case ChatMemberStatusRestricted:
+		m = &ChatMemberRestricted{}
+	case ChatMemberStatusLeft:
+		m = &ChatMemberLeft{}
+	case ChatMemberStatusBanned:
+		m = &ChatMemberBanned{}
+	default:
+		// a status added to the Bot API later must not fail the whole update
+		m = &ChatMemberUnknown{}
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// marshalChatMember encodes m together with its status, nil is encoded as
+// JSON null
+func marshalChatMember(m ChatMember) ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	if m, ok := m.(*ChatMemberUnknown); ok {
+		return json.Marshal(m)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, []byte("null")) {
+		// a nil pointer to one of the variants
+		return data, nil
+	}
+	if len(data) < 2 || data[0] != '{' {
+		return nil, fmt.Errorf("tbot: chat member %T is not encoded as a JSON object", m)
+	}
+	status, _ := json.Marshal(m.MemberStatus())
+	head := `{"status":` + string(status)
+	if len(data) > 2 {
+		head += ","
+	}
+	return append([]byte(head), data[1:]...), nil
+}
+
+// ChatMemberUpdated represents changes in the status of a chat member
+type ChatMemberUpdated struct {
+	Chat Chat `json:"chat"`
+	// From is the user who performed the change
+	From          *User           `json:"from"`
+	Date          int64           `json:"date"`
+	OldChatMember ChatMember      `json:"old_chat_member"`
+	NewChatMember ChatMember      `json:"new_chat_member"`
+	InviteLink    *ChatInviteLink `json:"invite_link,omitempty"`
+}
+
+type chatMemberUpdatedJSON struct {
+	Chat          Chat            `json:"chat"`
+	From          *User           `json:"from"`
+	Date          int64           `json:"date"`
+	OldChatMember json.RawMessage `json:"old_chat_member"`
+	NewChatMember json.RawMessage `json:"new_chat_member"`
+	InviteLink    *ChatInviteLink `json:"invite_link,omitempty"`
+}
+
+func (u *ChatMemberUpdated) UnmarshalJSON(data []byte) error {
+	var raw chatMemberUpdatedJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	old, err := unmarshalChatMember(raw.OldChatMember)
+	if err != nil {
+		return err
+	}
+	updated, err := unmarshalChatMember(raw.NewChatMember)
+	if err != nil {
+		return err
+	}
+	*u = ChatMemberUpdated{Chat: raw.Chat, From: raw.From, Date: raw.Date, OldChatMember: old, NewChatMember: updated, InviteLink: raw.InviteLink}
+	return nil
+}
+
+func (u ChatMemberUpdated) MarshalJSON() ([]byte, error) {
+	raw := chatMemberUpdatedJSON{Chat: u.Chat, From: u.From, Date: u.Date, InviteLink: u.InviteLink}
+	var err error
+	if raw.OldChatMember, err = marshalChatMember(u.OldChatMember); err != nil {
+		return nil, err
+	}
+	if raw.NewChatMember, err = marshalChatMember(u.NewChatMember); err != nil {
+		return nil, err
+	}
+	return json.Marshal(raw)
+}
+
+// IsChatMember tells whether m is currently in the chat
+func IsChatMember(m ChatMember) bool {
+	switch m := m.(type) {
+	case *ChatMemberOwner, *ChatMemberAdministrator, *ChatMemberMember:
+		return true
+	case *ChatMemberRestricted:
+		return m.IsMember
+	}
+	return false
+}
+
+// ChatMemberRights returns administrator rights of m, ok is false if m is
+// not an administrator. The owner has all rights.
+func ChatMemberRights(m ChatMember) (rights ChatAdministratorRights, ok bool) {
+	switch m := m.(type) {
+	case *ChatMemberOwner:
+		return ChatAdministratorRights{
+			IsAnonymous:         m.IsAnonymous,
+			CanManageChat:       true,
+			CanDeleteMessages:   true,
+			CanManageVideoChats: true,
+			CanRestrictMembers:  true,
+			CanPromoteMembers:   true,
+			CanChangeInfo:       true,
+			CanInviteUsers:      true,
+			CanPostMessages:     true,
+			CanEditMessages:     true,
+			CanPinMessages:      true,
+			CanManageTopics:     true,
+		}, true
+	case *ChatMemberAdministrator:
+		return m.ChatAdministratorRights, true
+	}
+	return ChatAdministratorRights{}, false
+}
+
+// GetChat returns up to date information about the chat
+func (c *Client) GetChat(chatID string) (*Chat, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	chat := &Chat{}
+	err := c.sendRequest("/getChat", req, chat)
+	return chat, err
+}
+
+// GetChatAdministrators returns administrators of the chat which aren't bots
+func (c *Client) GetChatAdministrators(chatID string) ([]ChatMember, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var raw []json.RawMessage
+	if err := c.sendRequest("/getChatAdministrators", req, &raw); err != nil {
+		return nil, err
+	}
+	members := make([]ChatMember, 0, len(raw))
+	for _, data := range raw {
+		m, err := unmarshalChatMember(data)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, nil
+}
+
+// GetChatMemberCount returns number of members in the chat
+func (c *Client) GetChatMemberCount(chatID string) (int, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var count int
+	err := c.sendRequest("/getChatMemberCount", req, &count)
+	return count, err
+}
+
+// GetChatMember returns membership of user in the chat
+func (c *Client) GetChatMember(chatID string, userID int) (ChatMember, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("user_id", strconv.Itoa(userID))
+	var raw json.RawMessage
+	if err := c.sendRequest("/getChatMember", req, &raw); err != nil {
+		return nil, err
+	}
+	return unmarshalChatMember(raw)
+}
+
+// LeaveChat makes the bot leave a group, supergroup or channel
+func (c *Client) LeaveChat(chatID string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var left bool
+	return c.sendRequest("/leaveChat", req, &left)
+}
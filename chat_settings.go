@@ -0,0 +1,98 @@
+package tbot
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// OptUnpinMessageID sets message UnpinChatMessage unpins, by default the most recent pinned message
+var OptUnpinMessageID = func(id int) sendOption {
+	return func(r url.Values) {
+		r.Set("message_id", strconv.Itoa(id))
+	}
+}
+
+// SetChatTitle changes title of a group, supergroup or channel
+func (c *Client) SetChatTitle(chatID, title string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("title", title)
+	var set bool
+	return c.sendRequest("/setChatTitle", req, &set)
+}
+
+// SetChatDescription changes description of a group, supergroup or channel,
+// empty description removes it
+func (c *Client) SetChatDescription(chatID, description string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("description", description)
+	var set bool
+	return c.sendRequest("/setChatDescription", req, &set)
+}
+
+// SetChatPhoto uploads file filename as photo of the chat
+func (c *Client) SetChatPhoto(chatID, filename string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var set bool
+	return c.sendRequestWithFiles("/setChatPhoto", req, &set, inputFile{field: "photo", name: filename})
+}
+
+// DeleteChatPhoto deletes photo of the chat
+func (c *Client) DeleteChatPhoto(chatID string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var deleted bool
+	return c.sendRequest("/deleteChatPhoto", req, &deleted)
+}
+
+// PinChatMessage pins message in the chat. Available options:
+//   - OptDisableNotification
+func (c *Client) PinChatMessage(chatID string, messageID int, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("message_id", strconv.Itoa(messageID))
+	for _, opt := range opts {
+		opt(req)
+	}
+	var pinned bool
+	return c.sendRequest("/pinChatMessage", req, &pinned)
+}
+
+// UnpinChatMessage unpins a pinned message of the chat. Available options:
+//   - OptUnpinMessageID(id int)
+func (c *Client) UnpinChatMessage(chatID string, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	for _, opt := range opts {
+		opt(req)
+	}
+	var unpinned bool
+	return c.sendRequest("/unpinChatMessage", req, &unpinned)
+}
+
+// UnpinAllChatMessages unpins all pinned messages of the chat
+func (c *Client) UnpinAllChatMessages(chatID string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var unpinned bool
+	return c.sendRequest("/unpinAllChatMessages", req, &unpinned)
+}
+
+// SetChatStickerSet sets group sticker set of a supergroup, see Chat.CanSetStickerSet
+func (c *Client) SetChatStickerSet(chatID, stickerSetName string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("sticker_set_name", stickerSetName)
+	var set bool
+	return c.sendRequest("/setChatStickerSet", req, &set)
+}
+
+// DeleteChatStickerSet deletes group sticker set of a supergroup
+func (c *Client) DeleteChatStickerSet(chatID string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var deleted bool
+	return c.sendRequest("/deleteChatStickerSet", req, &deleted)
+}
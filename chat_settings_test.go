@@ -0,0 +1,41 @@
+package tbot
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_SetChatPhoto(t *testing.T) {
+	var got, chatID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err == nil {
+			chatID = r.FormValue("chat_id")
+			if f, _, err := r.FormFile("photo"); err == nil {
+				data, _ := io.ReadAll(f)
+				got = string(data)
+			}
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	filename := filepath.Join(t.TempDir(), "photo.jpg")
+	if err := os.WriteFile(filename, []byte("jpeg"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient("token", srv.URL)
+	if err := c.SetChatPhoto("-5", filename); err != nil {
+		t.Fatalf("SetChatPhoto() error = %v", err)
+	}
+	if got != "jpeg" || chatID != "-5" {
+		t.Errorf("uploaded %q to chat %q, want %q to -5", got, chatID, "jpeg")
+	}
+	if err := c.SetChatPhoto("-5", filepath.Join(t.TempDir(), "missing.jpg")); err == nil {
+		t.Error("SetChatPhoto() of missing file succeeded")
+	}
+}
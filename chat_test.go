@@ -0,0 +1,90 @@
+package tbot
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalChatMember(t *testing.T) {
+	user := &User{ID: 7, FirstName: "Ani"}
+	tests := []struct {
+		name       string
+		data       string
+		want       ChatMember
+		member     bool
+		restricter bool
+	}{
+		{
+			name:       "owner",
+			data:       `{"status":"creator","user":{"id":7,"first_name":"Ani"},"is_anonymous":true}`,
+			want:       &ChatMemberOwner{User: user, IsAnonymous: true},
+			member:     true,
+			restricter: true,
+		},
+		{
+			name: "administrator",
+			data: `{"status":"administrator","user":{"id":7,"first_name":"Ani"},"can_be_edited":true,"can_restrict_members":true,"custom_title":"mod"}`,
+			want: &ChatMemberAdministrator{
+				ChatAdministratorRights: ChatAdministratorRights{CanRestrictMembers: true},
+				User:                    user,
+				CanBeEdited:             true,
+				CustomTitle:             "mod",
+			},
+			member:     true,
+			restricter: true,
+		},
+		{
+			name:   "restricted",
+			data:   `{"status":"restricted","user":{"id":7,"first_name":"Ani"},"is_member":true,"can_send_messages":true,"until_date":0}`,
+			want:   &ChatMemberRestricted{ChatPermissions: ChatPermissions{CanSendMessages: true}, User: user, IsMember: true},
+			member: true,
+		},
+		{
+			name: "banned",
+			data: `{"status":"kicked","user":{"id":7,"first_name":"Ani"},"until_date":1700000000}`,
+			want: &ChatMemberBanned{User: user, UntilDate: 1700000000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := unmarshalChatMember([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("unmarshalChatMember() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unmarshalChatMember() = %+v, want %+v", got, tt.want)
+			}
+			if got.MemberUser().ID != 7 {
+				t.Errorf("MemberUser() = %+v", got.MemberUser())
+			}
+			if IsChatMember(got) != tt.member {
+				t.Errorf("IsChatMember() = %v, want %v", !tt.member, tt.member)
+			}
+			rights, _ := ChatMemberRights(got)
+			if rights.CanRestrictMembers != tt.restricter {
+				t.Errorf("CanRestrictMembers = %v, want %v", rights.CanRestrictMembers, tt.restricter)
+			}
+		})
+	}
+
+	// a status added to the Bot API later must not fail decoding of the update
+	u := &Update{}
+	data := `{"update_id":1,"chat_member":{"chat":{"id":-5},"date":1,
+		"old_chat_member":{"status":"member","user":{"id":2}},
+		"new_chat_member":{"status":"guest","user":{"id":2}}}}`
+	if err := json.Unmarshal([]byte(data), u); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	unknown, ok := u.ChatMember.NewChatMember.(*ChatMemberUnknown)
+	if !ok || unknown.Status != "guest" || unknown.User.ID != 2 || IsChatMember(unknown) {
+		t.Errorf("NewChatMember = %#v, want unknown member with status guest", u.ChatMember.NewChatMember)
+	}
+	encoded, err := marshalChatMember(unknown)
+	if err != nil {
+		t.Fatalf("marshalChatMember() error = %v", err)
+	}
+	if decoded, err := unmarshalChatMember(encoded); err != nil || !reflect.DeepEqual(decoded, unknown) {
+		t.Errorf("round trip = %#v, %v, want %#v", decoded, err, unknown)
+	}
+}
@@ -16,6 +16,8 @@ type Client struct {
 	timeout      int
 	bufferSize   int
 	nextOffset   int
+	offsets      *OffsetTracker
+	migrations   *ChatMigrations
 	logger       Logger
 }
 
@@ -33,15 +35,22 @@ var (
 	OptSendingWithoutReply = func(r url.Values) { r.Set("allow_sending_without_reply", "true") }
 )
 
-func NewClient(token string, baseURL string) *Client {
+func NewClient(token string, baseURL string, opts ...ClientOptions) *Client {
 	if baseURL == "" {
 		baseURL = apiBaseURL
 	}
-	return &Client{
-		token:   token,
-		baseURL: baseURL,
-		url:     fmt.Sprintf("%s/bot%s", baseURL, token) + "%s",
+	c := &Client{
+		token:      token,
+		baseURL:    baseURL,
+		timeout:    30,
+		bufferSize: 100,
+		logger:     nopLogger{},
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.url = fmt.Sprintf("%s/bot%s", c.baseURL, token) + "%s"
+	return c
 }
 
 func structString(s any) string {
@@ -94,6 +103,20 @@ type ChatPermissions struct {
 	CanInviteUsers bool `json:"can_invite_users,omitempty"`
 	// CanPinMessages True, if the user is allowed to pin messages. Ignored in public supergroups
 	CanPinMessages bool `json:"can_pin_messages,omitempty"`
+	// CanSendAudios True, if the user is allowed to send audios
+	CanSendAudios bool `json:"can_send_audios,omitempty"`
+	// CanSendDocuments True, if the user is allowed to send documents
+	CanSendDocuments bool `json:"can_send_documents,omitempty"`
+	// CanSendPhotos True, if the user is allowed to send photos
+	CanSendPhotos bool `json:"can_send_photos,omitempty"`
+	// CanSendVideos True, if the user is allowed to send videos
+	CanSendVideos bool `json:"can_send_videos,omitempty"`
+	// CanSendVideoNotes True, if the user is allowed to send video notes
+	CanSendVideoNotes bool `json:"can_send_video_notes,omitempty"`
+	// CanSendVoiceNotes True, if the user is allowed to send voice notes
+	CanSendVoiceNotes bool `json:"can_send_voice_notes,omitempty"`
+	// CanManageTopics True, if the user is allowed to create forum topics, implies can_pin_messages if omitted
+	CanManageTopics bool `json:"can_manage_topics,omitempty"`
 }
 
 type replyKeyboardRemove struct {
@@ -140,6 +163,7 @@ var (
 // SendMessage sends message to telegram chat. Available options
 //   - OptParseModeHTML
 //   - OptParseModeMarkdown
+//   - OptEntities(entities []*MessageEntity)
 //   - OptDisableWebPagePreview
 //   - OptDisableNotification
 //   - OptReplyToMessageID(id int)
@@ -150,6 +174,7 @@ var (
 //   - OptReplyKeyboardRemoveSelective
 //   - OptForceReply
 //   - OptForceReplySelective
+//   - OptSplitLongMessage, the first of sent messages is returned
 func (c *Client) SendMessage(chatID string, thread_id string, text string, opts ...sendOption) (*Message, error) {
 	req := url.Values{}
 	req.Set("chat_id", chatID)
@@ -161,6 +186,14 @@ func (c *Client) SendMessage(chatID string, thread_id string, text string, opts
 	for _, opt := range opts {
 		opt(req)
 	}
+	if req.Get(splitLongMessageKey) != "" {
+		req.Del(splitLongMessageKey)
+		msgs, err := c.sendLongMessage(req)
+		if len(msgs) == 0 {
+			return &Message{}, err
+		}
+		return msgs[0], err
+	}
 	msg := &Message{}
 	err := c.sendRequest("/sendMessage", req, msg)
 	return msg, err
@@ -227,6 +260,37 @@ func (c *Client) SendSticker(chatID, fileID string, opts ...sendOption) (*Messag
 	return msg, err
 }
 
+// SendPhoto sends photo by file_id or HTTP URL. Available options:
+//   - OptParseModeHTML
+//   - OptParseModeMarkdown
+//   - OptCaptionEntities(entities []*MessageEntity)
+//   - OptDisableNotification
+//   - OptReplyToMessageID(id int)
+//   - OptInlineKeyboardMarkup(markup *InlineKeyboardMarkup)
+//   - OptReplyKeyboardMarkup(markup *ReplyKeyboardMarkup)
+//   - OptSplitLongMessage, caption over MaxCaptionLength continues in text
+//     messages replying to the photo
+func (c *Client) SendPhoto(chatID, photo, caption string, opts ...sendOption) (*Message, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("photo", photo)
+	if caption != "" {
+		req.Set("caption", caption)
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+	if req.Get(splitLongMessageKey) != "" {
+		req.Del(splitLongMessageKey)
+		if utf16Len(caption) > MaxCaptionLength {
+			return c.sendPhotoWithOverflow(req)
+		}
+	}
+	msg := &Message{}
+	err := c.sendRequest("/sendPhoto", req, msg)
+	return msg, err
+}
+
 type chatAction string
 
 // Actions for SendChatAction
@@ -250,3 +314,60 @@ func (c *Client) SendChatAction(chatID string, action chatAction) error {
 	var sent bool
 	return c.sendRequest("sendChatAction", req, &sent)
 }
+
+// EditMessageText edits text of a message sent by the bot. Available options:
+//   - OptParseModeHTML
+//   - OptParseModeMarkdown
+//   - OptDisableWebPagePreview
+//   - OptInlineKeyboardMarkup(markup *InlineKeyboardMarkup)
+func (c *Client) EditMessageText(chatID string, messageID int, text string, opts ...sendOption) (*Message, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("message_id", strconv.Itoa(messageID))
+	req.Set("text", text)
+	for _, opt := range opts {
+		opt(req)
+	}
+	msg := &Message{}
+	err := c.sendRequest("/editMessageText", req, msg)
+	return msg, err
+}
+
+// EditInlineMessageText edits text of a message sent via the bot in inline mode.
+// Available options are the same as for EditMessageText.
+func (c *Client) EditInlineMessageText(inlineMessageID string, text string, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("inline_message_id", inlineMessageID)
+	req.Set("text", text)
+	for _, opt := range opts {
+		opt(req)
+	}
+	var edited bool
+	return c.sendRequest("/editMessageText", req, &edited)
+}
+
+// EditMessageReplyMarkup edits inline keyboard of a message sent by the bot,
+// nil markup removes the keyboard
+func (c *Client) EditMessageReplyMarkup(chatID string, messageID int, markup *InlineKeyboardMarkup) (*Message, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("message_id", strconv.Itoa(messageID))
+	if markup != nil {
+		req.Set("reply_markup", structString(markup))
+	}
+	msg := &Message{}
+	err := c.sendRequest("/editMessageReplyMarkup", req, msg)
+	return msg, err
+}
+
+// EditInlineMessageReplyMarkup edits inline keyboard of a message sent via
+// the bot in inline mode, nil markup removes the keyboard
+func (c *Client) EditInlineMessageReplyMarkup(inlineMessageID string, markup *InlineKeyboardMarkup) error {
+	req := url.Values{}
+	req.Set("inline_message_id", inlineMessageID)
+	if markup != nil {
+		req.Set("reply_markup", structString(markup))
+	}
+	var edited bool
+	return c.sendRequest("/editMessageReplyMarkup", req, &edited)
+}
@@ -1,5 +1,9 @@
 package tbot
 
+import (
+	"net/url"
+)
+
 type ClientOptions func(*Client)
 
 func WithBaseURL(baseURL string) ClientOptions {
@@ -7,3 +11,54 @@ func WithBaseURL(baseURL string) ClientOptions {
 		client.baseURL = baseURL
 	}
 }
+
+// WithLogger sets logger used by the client
+func WithLogger(logger Logger) ClientOptions {
+	return func(client *Client) {
+		client.logger = logger
+	}
+}
+
+// WithPollTimeout sets long polling timeout in seconds for getUpdates
+func WithPollTimeout(timeout int) ClientOptions {
+	return func(client *Client) {
+		client.timeout = timeout
+	}
+}
+
+// WithPollLimit sets maximum number of updates fetched by a single getUpdates call
+func WithPollLimit(limit int) ClientOptions {
+	return func(client *Client) {
+		client.bufferSize = limit
+	}
+}
+
+// WithOffsetTracker makes polling resume from the offset persisted by tracker
+// and skip updates the tracker has already seen. The tracker only advances
+// when handlers wrapped by OffsetTracker.Handler succeed.
+func WithOffsetTracker(tracker *OffsetTracker) ClientOptions {
+	return func(client *Client) {
+		client.offsets = tracker
+	}
+}
+
+// WithAllowedUpdates sets types of updates polling receives, e.g. "message",
+// "callback_query", "my_chat_member" or "chat_member". By default all types
+// except "chat_member" are received.
+func WithAllowedUpdates(types ...string) ClientOptions {
+	return func(client *Client) {
+		if client.updateParams == nil {
+			client.updateParams = url.Values{}
+		}
+		client.updateParams.Set("allowed_updates", structString(types))
+	}
+}
+
+// WithChatMigrations makes requests follow groups migrated to supergroups:
+// chat ids recorded by migrations are replaced with the new ones, and a request
+// failing because its group was migrated is recorded and sent again.
+func WithChatMigrations(migrations *ChatMigrations) ClientOptions {
+	return func(client *Client) {
+		client.migrations = migrations
+	}
+}
@@ -0,0 +1,80 @@
+package tbot
+
+import (
+	"net/url"
+)
+
+// BotCommand is a command shown in the menu of the bot
+type BotCommand struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// BotCommandScope is the set of chats and users commands are shown to
+type BotCommandScope struct {
+	Type   string `json:"type"`
+	ChatID int    `json:"chat_id,omitempty"`
+	UserID int    `json:"user_id,omitempty"`
+}
+
+// Types of BotCommandScope
+const (
+	CommandScopeDefault               = "default"
+	CommandScopeAllPrivateChats       = "all_private_chats"
+	CommandScopeAllGroupChats         = "all_group_chats"
+	CommandScopeAllChatAdministrators = "all_chat_administrators"
+	CommandScopeChat                  = "chat"
+	CommandScopeChatAdministrators    = "chat_administrators"
+	CommandScopeChatMember            = "chat_member"
+)
+
+var (
+	OptCommandsScope = func(scope *BotCommandScope) sendOption {
+		return func(r url.Values) {
+			r.Set("scope", structString(scope))
+		}
+	}
+	// OptCommandsLanguage sets two-letter ISO 639-1 language of users the
+	// commands are shown to
+	OptCommandsLanguage = func(lang string) sendOption {
+		return func(r url.Values) {
+			r.Set("language_code", lang)
+		}
+	}
+)
+
+// SetMyCommands sets commands of the bot. Available options:
+//   - OptCommandsScope(scope *BotCommandScope)
+//   - OptCommandsLanguage(lang string)
+func (c *Client) SetMyCommands(commands []BotCommand, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("commands", structString(commands))
+	for _, opt := range opts {
+		opt(req)
+	}
+	var set bool
+	return c.sendRequest("/setMyCommands", req, &set)
+}
+
+// DeleteMyCommands deletes commands of the bot for the scope and language.
+// Available options are the same as for SetMyCommands.
+func (c *Client) DeleteMyCommands(opts ...sendOption) error {
+	req := url.Values{}
+	for _, opt := range opts {
+		opt(req)
+	}
+	var deleted bool
+	return c.sendRequest("/deleteMyCommands", req, &deleted)
+}
+
+// GetMyCommands returns commands of the bot for the scope and language.
+// Available options are the same as for SetMyCommands.
+func (c *Client) GetMyCommands(opts ...sendOption) ([]BotCommand, error) {
+	req := url.Values{}
+	for _, opt := range opts {
+		opt(req)
+	}
+	var commands []BotCommand
+	err := c.sendRequest("/getMyCommands", req, &commands)
+	return commands, err
+}
@@ -0,0 +1,322 @@
+package tbot
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// EntityText returns substring of text covered by entity. Entity offsets are
+// in UTF-16 code units, so characters outside the BMP count twice.
+func EntityText(text string, e *MessageEntity) string {
+	units := utf16.Encode([]rune(text))
+	start, end := clampEntity(e, len(units))
+	return string(utf16.Decode(units[start:end]))
+}
+
+func clampEntity(e *MessageEntity, n int) (int, int) {
+	start, end := e.Offset, e.Offset+e.Length
+	if start < 0 {
+		start = 0
+	}
+	if end > n {
+		end = n
+	}
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+// text returns text of the message and its entities, caption is used for media messages
+func (m *Message) text() (string, []*MessageEntity) {
+	if m.Text == "" && m.Caption != "" {
+		return m.Caption, m.CaptionEntities
+	}
+	return m.Text, m.Entities
+}
+
+// EntityText returns substring of the message text (or caption) covered by entity
+func (m *Message) EntityText(e *MessageEntity) string {
+	text, _ := m.text()
+	return EntityText(text, e)
+}
+
+func (m *Message) entityTexts(types ...string) []string {
+	text, entities := m.text()
+	var texts []string
+	for _, e := range entities {
+		for _, t := range types {
+			if e.Type == t {
+				texts = append(texts, EntityText(text, e))
+				break
+			}
+		}
+	}
+	return texts
+}
+
+// Mentions returns "@username" mentions and text mentions of users without username
+func (m *Message) Mentions() []string {
+	return m.entityTexts(EntityMention, EntityTextMention)
+}
+
+// Hashtags returns hashtags including the leading '#'
+func (m *Message) Hashtags() []string {
+	return m.entityTexts(EntityHashtag)
+}
+
+// Commands returns bot commands including the leading '/' and "@botname" suffix if any
+func (m *Message) Commands() []string {
+	return m.entityTexts(EntityBotCommand)
+}
+
+// URLs returns URLs written in the text and targets of text links
+func (m *Message) URLs() []string {
+	text, entities := m.text()
+	var urls []string
+	for _, e := range entities {
+		switch e.Type {
+		case EntityURL:
+			urls = append(urls, EntityText(text, e))
+		case EntityTextLink:
+			urls = append(urls, e.URL)
+		}
+	}
+	return urls
+}
+
+// HTML renders the message text (or caption) with its entities for OptParseModeHTML
+func (m *Message) HTML() string {
+	text, entities := m.text()
+	return RenderHTML(text, entities)
+}
+
+// MarkdownV2 renders the message text (or caption) with its entities for OptParseModeMarkdown
+func (m *Message) MarkdownV2() string {
+	text, entities := m.text()
+	return RenderMarkdownV2(text, entities)
+}
+
+// RenderHTML renders text with entities as HTML parse mode markup
+func RenderHTML(text string, entities []*MessageEntity) string {
+	return renderEntities(text, entities, htmlMarkup{})
+}
+
+// RenderMarkdownV2 renders text with entities as MarkdownV2 parse mode markup
+func RenderMarkdownV2(text string, entities []*MessageEntity) string {
+	return renderEntities(text, entities, markdownV2Markup{})
+}
+
+type entityMarkup interface {
+	supports(e *MessageEntity) bool
+	open(b *strings.Builder, e *MessageEntity)
+	close(b *strings.Builder, e *MessageEntity)
+	// text writes s found inside of the open entities
+	text(b *strings.Builder, s string, open []*MessageEntity)
+}
+
+// renderEntities writes text split at entity boundaries. Entities are kept
+// on a stack of open tags, overlapping entities are closed and reopened so
+// the markup is always properly nested.
+func renderEntities(text string, entities []*MessageEntity, markup entityMarkup) string {
+	units := utf16.Encode([]rune(text))
+	sorted := make([]*MessageEntity, 0, len(entities))
+	bounds := []int{0, len(units)}
+	for _, e := range entities {
+		start, end := clampEntity(e, len(units))
+		if start == end || !markup.supports(e) {
+			continue
+		}
+		sorted = append(sorted, e)
+		bounds = append(bounds, start, end)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Offset != sorted[j].Offset {
+			return sorted[i].Offset < sorted[j].Offset
+		}
+		return sorted[i].Length > sorted[j].Length
+	})
+	sort.Ints(bounds)
+
+	var b strings.Builder
+	var stack []*MessageEntity
+	for i := 0; i+1 < len(bounds); i++ {
+		start, end := bounds[i], bounds[i+1]
+		if start == end {
+			continue
+		}
+		var active []*MessageEntity
+		for _, e := range sorted {
+			if e.Offset <= start && e.Offset+e.Length >= end {
+				active = append(active, e)
+			}
+		}
+		common := 0
+		for common < len(stack) && common < len(active) && stack[common] == active[common] {
+			common++
+		}
+		for j := len(stack) - 1; j >= common; j-- {
+			markup.close(&b, stack[j])
+		}
+		stack = append(stack[:common], active[common:]...)
+		for _, e := range active[common:] {
+			markup.open(&b, e)
+		}
+		markup.text(&b, string(utf16.Decode(units[start:end])), stack)
+	}
+	for j := len(stack) - 1; j >= 0; j-- {
+		markup.close(&b, stack[j])
+	}
+	return b.String()
+}
+
+func hasEntity(entities []*MessageEntity, types ...string) bool {
+	for _, e := range entities {
+		for _, t := range types {
+			if e.Type == t {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func userLink(u *User) string {
+	if u == nil {
+		return ""
+	}
+	return "tg://user?id=" + strconv.Itoa(u.ID)
+}
+
+type htmlMarkup struct{}
+
+var htmlTags = map[string]string{
+	EntityBold:          "b",
+	EntityItalic:        "i",
+	EntityUnderline:     "u",
+	EntityStrikethrough: "s",
+	EntitySpoiler:       "tg-spoiler",
+	EntityCode:          "code",
+	EntityBlockquote:    "blockquote",
+}
+
+func (htmlMarkup) supports(e *MessageEntity) bool {
+	switch e.Type {
+	case EntityPre, EntityTextLink, EntityTextMention, EntityCustomEmoji:
+		return true
+	}
+	_, ok := htmlTags[e.Type]
+	return ok
+}
+
+func (htmlMarkup) open(b *strings.Builder, e *MessageEntity) {
+	switch e.Type {
+	case EntityPre:
+		b.WriteString("<pre>")
+		if e.Language != "" {
+			b.WriteString(`<code class="language-` + EscapeHTML(e.Language) + `">`)
+		}
+	case EntityTextLink:
+		b.WriteString(`<a href="` + EscapeHTML(e.URL) + `">`)
+	case EntityTextMention:
+		b.WriteString(`<a href="` + userLink(e.User) + `">`)
+	case EntityCustomEmoji:
+		b.WriteString(`<tg-emoji emoji-id="` + EscapeHTML(e.CustomEmojiID) + `">`)
+	default:
+		b.WriteString("<" + htmlTags[e.Type] + ">")
+	}
+}
+
+func (htmlMarkup) close(b *strings.Builder, e *MessageEntity) {
+	switch e.Type {
+	case EntityPre:
+		if e.Language != "" {
+			b.WriteString("</code>")
+		}
+		b.WriteString("</pre>")
+	case EntityTextLink, EntityTextMention:
+		b.WriteString("</a>")
+	case EntityCustomEmoji:
+		b.WriteString("</tg-emoji>")
+	default:
+		b.WriteString("</" + htmlTags[e.Type] + ">")
+	}
+}
+
+func (htmlMarkup) text(b *strings.Builder, s string, open []*MessageEntity) {
+	b.WriteString(EscapeHTML(s))
+}
+
+type markdownV2Markup struct{}
+
+var markdownV2Marks = map[string]string{
+	EntityBold:          "*",
+	EntityItalic:        "_",
+	EntityUnderline:     "__",
+	EntityStrikethrough: "~",
+	EntitySpoiler:       "||",
+	EntityCode:          "`",
+}
+
+func (markdownV2Markup) supports(e *MessageEntity) bool {
+	switch e.Type {
+	case EntityPre, EntityTextLink, EntityTextMention, EntityCustomEmoji, EntityBlockquote:
+		return true
+	}
+	_, ok := markdownV2Marks[e.Type]
+	return ok
+}
+
+func (markdownV2Markup) open(b *strings.Builder, e *MessageEntity) {
+	switch e.Type {
+	case EntityPre:
+		b.WriteString("```" + e.Language + "\n")
+	case EntityTextLink, EntityTextMention:
+		b.WriteString("[")
+	case EntityCustomEmoji:
+		b.WriteString("![")
+	case EntityBlockquote:
+		// ">" is valid only at the start of a line, the lines of a quote
+		// starting mid-line are marked after their newlines by text
+		if b.Len() == 0 || strings.HasSuffix(b.String(), "\n") {
+			b.WriteString(">")
+		}
+	default:
+		b.WriteString(markdownV2Marks[e.Type])
+	}
+}
+
+func (markdownV2Markup) close(b *strings.Builder, e *MessageEntity) {
+	switch e.Type {
+	case EntityPre:
+		b.WriteString("\n```")
+	case EntityTextLink:
+		b.WriteString("](" + EscapeMarkdownV2URL(e.URL) + ")")
+	case EntityTextMention:
+		b.WriteString("](" + userLink(e.User) + ")")
+	case EntityCustomEmoji:
+		b.WriteString("](tg://emoji?id=" + EscapeMarkdownV2URL(e.CustomEmojiID) + ")")
+	case EntityBlockquote:
+	default:
+		b.WriteString(markdownV2Marks[e.Type])
+		if e.Type == EntityItalic {
+			// Telegram ignores \r, it keeps "_" of italic from merging
+			// into "__" of an enclosing underline
+			b.WriteString("\r")
+		}
+	}
+}
+
+func (markdownV2Markup) text(b *strings.Builder, s string, open []*MessageEntity) {
+	if hasEntity(open, EntityCode, EntityPre) {
+		s = EscapeMarkdownV2Code(s)
+	} else {
+		s = EscapeMarkdownV2(s)
+	}
+	if hasEntity(open, EntityBlockquote) {
+		s = strings.ReplaceAll(s, "\n", "\n>")
+	}
+	b.WriteString(s)
+}
@@ -0,0 +1,102 @@
+package tbot
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMessage_Entities(t *testing.T) {
+	m := &Message{
+		Text: "😀 /start@bot hi @joe #ops 𝕏 https://x.com docs",
+		Entities: []*MessageEntity{
+			{Type: EntityBotCommand, Offset: 3, Length: 10},
+			{Type: EntityMention, Offset: 17, Length: 4},
+			{Type: EntityHashtag, Offset: 22, Length: 4},
+			{Type: EntityURL, Offset: 30, Length: 13},
+			{Type: EntityTextLink, Offset: 44, Length: 4, URL: "https://docs.x.com"},
+		},
+	}
+	if got, want := m.Commands(), []string{"/start@bot"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Commands() = %q, want %q", got, want)
+	}
+	if got, want := m.Mentions(), []string{"@joe"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Mentions() = %q, want %q", got, want)
+	}
+	if got, want := m.Hashtags(), []string{"#ops"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Hashtags() = %q, want %q", got, want)
+	}
+	if got, want := m.URLs(), []string{"https://x.com", "https://docs.x.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("URLs() = %q, want %q", got, want)
+	}
+	if got := m.EntityText(m.Entities[4]); got != "docs" {
+		t.Errorf("EntityText() = %q, want %q", got, "docs")
+	}
+}
+
+func TestRenderEntities(t *testing.T) {
+	text := "😀 bold a<b> 1.5 x_y"
+	entities := []*MessageEntity{
+		{Type: EntityBold, Offset: 3, Length: 9},
+		{Type: EntityItalic, Offset: 8, Length: 8},
+		{Type: EntityCode, Offset: 17, Length: 3},
+		{Type: EntityMention, Offset: 0, Length: 2},
+	}
+	tests := []struct {
+		name   string
+		render func(string, []*MessageEntity) string
+		want   string
+	}{
+		{
+			name:   "html",
+			render: RenderHTML,
+			want:   "😀 <b>bold <i>a&lt;b&gt;</i></b><i> 1.5</i> <code>x_y</code>",
+		},
+		{
+			name:   "markdown",
+			render: RenderMarkdownV2,
+			want:   "😀 *bold _a<b\\>_\r*_ 1\\.5_\r `x_y`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.render(text, entities); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownV2_Blockquote(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		quote *MessageEntity
+		want  string
+	}{
+		{
+			name:  "whole text",
+			text:  "first\nsecond",
+			quote: &MessageEntity{Type: EntityBlockquote, Offset: 0, Length: 12},
+			want:  ">first\n>second",
+		},
+		{
+			name:  "after a line",
+			text:  "intro\nquoted",
+			quote: &MessageEntity{Type: EntityBlockquote, Offset: 6, Length: 6},
+			want:  "intro\n>quoted",
+		},
+		{
+			name:  "mid-line",
+			text:  "he said: hi\nthere",
+			quote: &MessageEntity{Type: EntityBlockquote, Offset: 9, Length: 8},
+			want:  "he said: hi\n>there",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMarkdownV2(tt.text, []*MessageEntity{tt.quote}); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
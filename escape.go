@@ -0,0 +1,211 @@
+package tbot
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// Parse modes of message text
+const (
+	ParseModeHTML       = "HTML"
+	ParseModeMarkdownV2 = "MarkdownV2"
+)
+
+var (
+	markdownV2Escaper = strings.NewReplacer(
+		`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+		"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+		"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+	)
+	markdownV2CodeEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`")
+	markdownV2URLEscaper  = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+	htmlEscaper           = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+// EscapeMarkdownV2 escapes s for use as plain text in MarkdownV2
+func EscapeMarkdownV2(s string) string {
+	return markdownV2Escaper.Replace(s)
+}
+
+// EscapeMarkdownV2Code escapes s for use inside `code` and ```pre``` MarkdownV2 entities
+func EscapeMarkdownV2Code(s string) string {
+	return markdownV2CodeEscaper.Replace(s)
+}
+
+// EscapeMarkdownV2URL escapes s for use as URL inside (...) of a MarkdownV2 inline link
+func EscapeMarkdownV2URL(s string) string {
+	return markdownV2URLEscaper.Replace(s)
+}
+
+// EscapeHTML escapes s for use as text or attribute value in HTML parse mode
+func EscapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// Raw is template output which is already formatted for the parse mode and is not escaped again
+type Raw string
+
+const templateEscapeFunc = "_tbotEscape"
+
+// Template renders message text with text/template, escaping the output of
+// every action for the parse mode, so untrusted values can be interpolated
+// directly: {{.LogLine}} is always shown literally. Formatting is added with
+// functions which escape their arguments for the right context:
+//
+//	{{bold .Title}} {{italic .Service}}
+//	{{code .Command}}
+//	{{pre .LogLines "text"}}
+//	{{link .Title .URL}}
+//	{{raw "<b>trusted</b>"}}
+//
+// Literal text of the template is written as is and must already be valid
+// for the parse mode.
+type Template struct {
+	tmpl      *template.Template
+	parseMode string
+}
+
+// NewTemplate parses text as template rendering for parseMode
+// (ParseModeHTML or ParseModeMarkdownV2). funcs may be nil.
+func NewTemplate(name, text, parseMode string, funcs template.FuncMap) (*Template, error) {
+	var escape func(string) string
+	var f formatter
+	switch parseMode {
+	case ParseModeHTML:
+		escape, f = EscapeHTML, htmlFormatter{}
+	case ParseModeMarkdownV2:
+		escape, f = EscapeMarkdownV2, markdownV2Formatter{}
+	default:
+		return nil, fmt.Errorf("tbot: unsupported parse mode %q", parseMode)
+	}
+
+	tmpl := template.New(name).Funcs(template.FuncMap{
+		templateEscapeFunc: func(v any) string {
+			if raw, ok := v.(Raw); ok {
+				return string(raw)
+			}
+			return escape(fmt.Sprint(v))
+		},
+		"raw":    func(s string) Raw { return Raw(s) },
+		"bold":   func(v any) Raw { return f.wrap(EntityBold, fmt.Sprint(v)) },
+		"italic": func(v any) Raw { return f.wrap(EntityItalic, fmt.Sprint(v)) },
+		"code":   func(v any) Raw { return f.code(fmt.Sprint(v)) },
+		"pre": func(v any, language ...string) Raw {
+			return f.pre(fmt.Sprint(v), strings.Join(language, ""))
+		},
+		"link": func(text, url any) Raw { return f.link(fmt.Sprint(text), fmt.Sprint(url)) },
+	})
+	if funcs != nil {
+		tmpl = tmpl.Funcs(funcs)
+	}
+	tmpl, err := tmpl.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			escapeTemplateNode(t.Tree, t.Tree.Root)
+		}
+	}
+	return &Template{tmpl: tmpl, parseMode: parseMode}, nil
+}
+
+// Execute renders the template
+func (t *Template) Execute(data any) (string, error) {
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Option returns send option setting parse mode of the template
+func (t *Template) Option() sendOption {
+	if t.parseMode == ParseModeHTML {
+		return OptParseModeHTML
+	}
+	return OptParseModeMarkdown
+}
+
+// escapeTemplateNode pipes the output of every action to the escape function
+func escapeTemplateNode(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 {
+			// variable declarations print nothing
+			return
+		}
+		ident := parse.NewIdentifier(templateEscapeFunc).SetTree(tree).SetPos(n.Pos)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{
+			NodeType: parse.NodeCommand,
+			Pos:      n.Pos,
+			Args:     []parse.Node{ident},
+		})
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			escapeTemplateNode(tree, child)
+		}
+	case *parse.IfNode:
+		escapeTemplateNode(tree, n.List)
+		escapeTemplateNode(tree, n.ElseList)
+	case *parse.RangeNode:
+		escapeTemplateNode(tree, n.List)
+		escapeTemplateNode(tree, n.ElseList)
+	case *parse.WithNode:
+		escapeTemplateNode(tree, n.List)
+		escapeTemplateNode(tree, n.ElseList)
+	}
+}
+
+type formatter interface {
+	wrap(entityType, s string) Raw
+	code(s string) Raw
+	pre(s, language string) Raw
+	link(text, url string) Raw
+}
+
+type htmlFormatter struct{}
+
+func (htmlFormatter) wrap(entityType, s string) Raw {
+	tag := map[string]string{EntityBold: "b", EntityItalic: "i"}[entityType]
+	return Raw("<" + tag + ">" + EscapeHTML(s) + "</" + tag + ">")
+}
+
+func (htmlFormatter) code(s string) Raw {
+	return Raw("<code>" + EscapeHTML(s) + "</code>")
+}
+
+func (htmlFormatter) pre(s, language string) Raw {
+	if language == "" {
+		return Raw("<pre>" + EscapeHTML(s) + "</pre>")
+	}
+	return Raw(`<pre><code class="language-` + EscapeHTML(language) + `">` + EscapeHTML(s) + "</code></pre>")
+}
+
+func (htmlFormatter) link(text, url string) Raw {
+	return Raw(`<a href="` + EscapeHTML(url) + `">` + EscapeHTML(text) + "</a>")
+}
+
+type markdownV2Formatter struct{}
+
+func (markdownV2Formatter) wrap(entityType, s string) Raw {
+	mark := map[string]string{EntityBold: "*", EntityItalic: "_"}[entityType]
+	return Raw(mark + EscapeMarkdownV2(s) + mark)
+}
+
+func (markdownV2Formatter) code(s string) Raw {
+	return Raw("`" + EscapeMarkdownV2Code(s) + "`")
+}
+
+func (markdownV2Formatter) pre(s, language string) Raw {
+	return Raw("```" + language + "\n" + EscapeMarkdownV2Code(s) + "\n```")
+}
+
+func (markdownV2Formatter) link(text, url string) Raw {
+	return Raw("[" + EscapeMarkdownV2(text) + "](" + EscapeMarkdownV2URL(url) + ")")
+}
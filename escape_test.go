@@ -0,0 +1,64 @@
+package tbot
+
+import (
+	"testing"
+)
+
+func TestEscapeMarkdownV2(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(string) string
+		in   string
+		want string
+	}{
+		{name: "text", fn: EscapeMarkdownV2, in: `a_b.c [x](y) \`, want: `a\_b\.c \[x\]\(y\) \\`},
+		{name: "code", fn: EscapeMarkdownV2Code, in: "a_b `c` \\", want: "a_b \\`c\\` \\\\"},
+		{name: "url", fn: EscapeMarkdownV2URL, in: `https://x.com/a_(b)`, want: `https://x.com/a_(b\)`},
+		{name: "html", fn: EscapeHTML, in: `<a href="x">&</a>`, want: `&lt;a href=&quot;x&quot;&gt;&amp;&lt;/a&gt;`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_Execute(t *testing.T) {
+	data := map[string]any{"Title": "db_1 down!", "Line": "<err> `x`", "URL": "https://x.com/a)b", "Items": []string{"a", "b"}}
+	tests := []struct {
+		name      string
+		parseMode string
+		text      string
+		want      string
+	}{
+		{
+			name:      "markdown",
+			parseMode: ParseModeMarkdownV2,
+			text:      `{{bold .Title}} {{$l := .Line}}{{range $i, $c := .Items}}{{$i}}\.{{end}} {{code $l}} {{link .Title .URL}} {{raw "*ok*"}}`,
+			want:      "*db\\_1 down\\!* 0\\.1\\. `<err> \\`x\\`` [db\\_1 down\\!](https://x.com/a\\)b) *ok*",
+		},
+		{
+			name:      "html",
+			parseMode: ParseModeHTML,
+			text:      `{{.Title}}: {{pre .Line "log"}}{{if .Line}} {{.Line}}{{end}}`,
+			want:      `db_1 down!: <pre><code class="language-log">&lt;err&gt; ` + "`x`" + `</code></pre> &lt;err&gt; ` + "`x`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := NewTemplate(tt.name, tt.text, tt.parseMode, nil)
+			if err != nil {
+				t.Fatalf("NewTemplate() error = %v", err)
+			}
+			got, err := tmpl.Execute(data)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Execute() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package tbot
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// Colors of forum topic icons, the only ones accepted by Telegram
+const (
+	TopicColorBlue   = 0x6FB9F0
+	TopicColorYellow = 0xFFD67E
+	TopicColorViolet = 0xCB86DB
+	TopicColorGreen  = 0x8EEE98
+	TopicColorRose   = 0xFF93B2
+	TopicColorRed    = 0xFB6F5F
+)
+
+// ForumTopic represents a forum topic. Messages are sent to the topic by
+// passing strconv.Itoa(MessageThreadID) as thread id to SendMessage.
+type ForumTopic struct {
+	MessageThreadID   int    `json:"message_thread_id"`
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+// ForumTopicCreated is a service message about a new forum topic
+type ForumTopicCreated struct {
+	Name              string `json:"name"`
+	IconColor         int    `json:"icon_color"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+// ForumTopicEdited is a service message about an edited forum topic, empty
+// fields were not changed
+type ForumTopicEdited struct {
+	Name              string  `json:"name,omitempty"`
+	IconCustomEmojiID *string `json:"icon_custom_emoji_id,omitempty"`
+}
+
+// ForumTopicClosed is a service message about a forum topic closed in the chat
+type ForumTopicClosed struct{}
+
+// ForumTopicReopened is a service message about a forum topic reopened in the chat
+type ForumTopicReopened struct{}
+
+// GeneralForumTopicHidden is a service message about the General forum topic hidden in the chat
+type GeneralForumTopicHidden struct{}
+
+// GeneralForumTopicUnhidden is a service message about the General forum topic unhidden in the chat
+type GeneralForumTopicUnhidden struct{}
+
+var (
+	// OptTopicIconColor sets icon color of a new topic, one of TopicColor constants
+	OptTopicIconColor = func(color int) sendOption {
+		return func(r url.Values) {
+			r.Set("icon_color", strconv.Itoa(color))
+		}
+	}
+	// OptTopicIconCustomEmojiID sets custom emoji shown as the topic icon,
+	// see GetForumTopicIconStickers. Empty id removes the icon when editing.
+	OptTopicIconCustomEmojiID = func(id string) sendOption {
+		return func(r url.Values) {
+			r.Set("icon_custom_emoji_id", id)
+		}
+	}
+	// OptTopicName sets new name of an edited topic
+	OptTopicName = func(name string) sendOption {
+		return func(r url.Values) {
+			r.Set("name", name)
+		}
+	}
+)
+
+// CreateForumTopic creates a topic in a forum supergroup. Available options:
+//   - OptTopicIconColor(color int)
+//   - OptTopicIconCustomEmojiID(id string)
+func (c *Client) CreateForumTopic(chatID, name string, opts ...sendOption) (*ForumTopic, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("name", name)
+	for _, opt := range opts {
+		opt(req)
+	}
+	topic := &ForumTopic{}
+	err := c.sendRequest("/createForumTopic", req, topic)
+	return topic, err
+}
+
+// EditForumTopic edits name and icon of a topic. Available options:
+//   - OptTopicName(name string)
+//   - OptTopicIconCustomEmojiID(id string)
+func (c *Client) EditForumTopic(chatID string, threadID int, opts ...sendOption) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("message_thread_id", strconv.Itoa(threadID))
+	for _, opt := range opts {
+		opt(req)
+	}
+	var edited bool
+	return c.sendRequest("/editForumTopic", req, &edited)
+}
+
+func (c *Client) forumTopicRequest(method, chatID string, threadID int) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("message_thread_id", strconv.Itoa(threadID))
+	var ok bool
+	return c.sendRequest(method, req, &ok)
+}
+
+// CloseForumTopic closes an open topic
+func (c *Client) CloseForumTopic(chatID string, threadID int) error {
+	return c.forumTopicRequest("/closeForumTopic", chatID, threadID)
+}
+
+// ReopenForumTopic reopens a closed topic
+func (c *Client) ReopenForumTopic(chatID string, threadID int) error {
+	return c.forumTopicRequest("/reopenForumTopic", chatID, threadID)
+}
+
+// DeleteForumTopic deletes a topic along with all its messages
+func (c *Client) DeleteForumTopic(chatID string, threadID int) error {
+	return c.forumTopicRequest("/deleteForumTopic", chatID, threadID)
+}
+
+// UnpinAllForumTopicMessages unpins all messages of a topic
+func (c *Client) UnpinAllForumTopicMessages(chatID string, threadID int) error {
+	return c.forumTopicRequest("/unpinAllForumTopicMessages", chatID, threadID)
+}
+
+func (c *Client) generalForumTopicRequest(method, chatID string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var ok bool
+	return c.sendRequest(method, req, &ok)
+}
+
+// EditGeneralForumTopic renames the General topic
+func (c *Client) EditGeneralForumTopic(chatID, name string) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("name", name)
+	var edited bool
+	return c.sendRequest("/editGeneralForumTopic", req, &edited)
+}
+
+// CloseGeneralForumTopic closes the General topic
+func (c *Client) CloseGeneralForumTopic(chatID string) error {
+	return c.generalForumTopicRequest("/closeGeneralForumTopic", chatID)
+}
+
+// ReopenGeneralForumTopic reopens the General topic, it is unhidden if it was hidden
+func (c *Client) ReopenGeneralForumTopic(chatID string) error {
+	return c.generalForumTopicRequest("/reopenGeneralForumTopic", chatID)
+}
+
+// HideGeneralForumTopic hides the General topic, it is closed if it was open
+func (c *Client) HideGeneralForumTopic(chatID string) error {
+	return c.generalForumTopicRequest("/hideGeneralForumTopic", chatID)
+}
+
+// UnhideGeneralForumTopic unhides the General topic
+func (c *Client) UnhideGeneralForumTopic(chatID string) error {
+	return c.generalForumTopicRequest("/unhideGeneralForumTopic", chatID)
+}
+
+// UnpinAllGeneralForumTopicMessages unpins all messages of the General topic
+func (c *Client) UnpinAllGeneralForumTopicMessages(chatID string) error {
+	return c.generalForumTopicRequest("/unpinAllGeneralForumTopicMessages", chatID)
+}
+
+// GetForumTopicIconStickers returns custom emoji stickers which can be used as topic icons
+func (c *Client) GetForumTopicIconStickers() ([]Sticker, error) {
+	var stickers []Sticker
+	err := c.sendRequest("/getForumTopicIconStickers", nil, &stickers)
+	return stickers, err
+}
@@ -0,0 +1,150 @@
+package tbot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_CreateForumTopic(t *testing.T) {
+	var form url.Values
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form, path = r.Form, r.URL.Path
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_thread_id":42,"name":"Support","icon_color":7322096,"icon_custom_emoji_id":"e1"}}`)
+	}))
+	defer srv.Close()
+
+	c := NewClient("token", srv.URL)
+	topic, err := c.CreateForumTopic("-5", "Support", OptTopicIconColor(TopicColorBlue), OptTopicIconCustomEmojiID("e1"))
+	if err != nil {
+		t.Fatalf("CreateForumTopic() error = %v", err)
+	}
+	want := &ForumTopic{MessageThreadID: 42, Name: "Support", IconColor: TopicColorBlue, IconCustomEmojiID: "e1"}
+	if !reflect.DeepEqual(topic, want) {
+		t.Errorf("topic = %+v, want %+v", topic, want)
+	}
+	wantForm := url.Values{"chat_id": {"-5"}, "name": {"Support"}, "icon_color": {"7322096"}, "icon_custom_emoji_id": {"e1"}}
+	if path != "/bottoken/createForumTopic" || !reflect.DeepEqual(form, wantForm) {
+		t.Errorf("request %s %v, want /bottoken/createForumTopic %v", path, form, wantForm)
+	}
+}
+
+func TestClient_ForumTopicMethods(t *testing.T) {
+	var form url.Values
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form, path = r.Form, r.URL.Path
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	c := NewClient("token", srv.URL)
+	tests := []struct {
+		name string
+		call func() error
+		path string
+		form url.Values
+	}{
+		{
+			name: "edit",
+			call: func() error { return c.EditForumTopic("-5", 42, OptTopicName("Help"), OptTopicIconCustomEmojiID("")) },
+			path: "/bottoken/editForumTopic",
+			form: url.Values{"chat_id": {"-5"}, "message_thread_id": {"42"}, "name": {"Help"}, "icon_custom_emoji_id": {""}},
+		},
+		{
+			name: "close",
+			call: func() error { return c.CloseForumTopic("-5", 42) },
+			path: "/bottoken/closeForumTopic",
+			form: url.Values{"chat_id": {"-5"}, "message_thread_id": {"42"}},
+		},
+		{
+			name: "reopen",
+			call: func() error { return c.ReopenForumTopic("-5", 42) },
+			path: "/bottoken/reopenForumTopic",
+			form: url.Values{"chat_id": {"-5"}, "message_thread_id": {"42"}},
+		},
+		{
+			name: "delete",
+			call: func() error { return c.DeleteForumTopic("-5", 42) },
+			path: "/bottoken/deleteForumTopic",
+			form: url.Values{"chat_id": {"-5"}, "message_thread_id": {"42"}},
+		},
+		{
+			name: "unpin all",
+			call: func() error { return c.UnpinAllForumTopicMessages("-5", 42) },
+			path: "/bottoken/unpinAllForumTopicMessages",
+			form: url.Values{"chat_id": {"-5"}, "message_thread_id": {"42"}},
+		},
+		{
+			name: "edit general",
+			call: func() error { return c.EditGeneralForumTopic("-5", "Lobby") },
+			path: "/bottoken/editGeneralForumTopic",
+			form: url.Values{"chat_id": {"-5"}, "name": {"Lobby"}},
+		},
+		{
+			name: "close general",
+			call: func() error { return c.CloseGeneralForumTopic("-5") },
+			path: "/bottoken/closeGeneralForumTopic",
+			form: url.Values{"chat_id": {"-5"}},
+		},
+		{
+			name: "reopen general",
+			call: func() error { return c.ReopenGeneralForumTopic("-5") },
+			path: "/bottoken/reopenGeneralForumTopic",
+			form: url.Values{"chat_id": {"-5"}},
+		},
+		{
+			name: "hide general",
+			call: func() error { return c.HideGeneralForumTopic("-5") },
+			path: "/bottoken/hideGeneralForumTopic",
+			form: url.Values{"chat_id": {"-5"}},
+		},
+		{
+			name: "unhide general",
+			call: func() error { return c.UnhideGeneralForumTopic("-5") },
+			path: "/bottoken/unhideGeneralForumTopic",
+			form: url.Values{"chat_id": {"-5"}},
+		},
+		{
+			name: "unpin all general",
+			call: func() error { return c.UnpinAllGeneralForumTopicMessages("-5") },
+			path: "/bottoken/unpinAllGeneralForumTopicMessages",
+			form: url.Values{"chat_id": {"-5"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form, path = nil, ""
+			if err := tt.call(); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if path != tt.path || !reflect.DeepEqual(form, tt.form) {
+				t.Errorf("request %s %v, want %s %v", path, form, tt.path, tt.form)
+			}
+		})
+	}
+}
+
+func TestClient_GetForumTopicIconStickers(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottoken/getForumTopicIconStickers" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":[{"file_id":"f1","file_unique_id":"u1","emoji":"🔥"}]}`)
+	}))
+	defer srv.Close()
+
+	stickers, err := NewClient("token", srv.URL).GetForumTopicIconStickers()
+	if err != nil {
+		t.Fatalf("GetForumTopicIconStickers() error = %v", err)
+	}
+	if len(stickers) != 1 || stickers[0].FileID != "f1" || stickers[0].Emoji != "🔥" {
+		t.Errorf("stickers = %+v", stickers)
+	}
+}
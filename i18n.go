@@ -0,0 +1,404 @@
+package tbot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PluralRule returns plural category ("zero", "one", "two", "few", "many"
+// or "other") of n in a language
+type PluralRule func(n int) string
+
+var defaultPluralRules = map[string]PluralRule{
+	"en": func(n int) string {
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	},
+	// Indonesian nouns do not change with number
+	"id": func(n int) string { return "other" },
+}
+
+var pluralCategories = map[string]bool{"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true}
+
+// message is a catalog entry, either a text or texts by plural category
+type message struct {
+	text  string
+	forms map[string]string
+}
+
+// Bundle holds message catalogs of all languages of the bot. Catalogs are
+// JSON files named by language, e.g. "en.json" and "id.json":
+//
+//	{
+//		"greeting": "Hello, {0}!",
+//		"incidents": {"one": "{n} open incident", "other": "{n} open incidents"},
+//		"command": {"start": "Start the bot"}
+//	}
+//
+// Nested objects are flattened to dotted keys ("command.start"), objects
+// with plural categories as keys are plural messages. "{0}", "{1}"... are
+// replaced by arguments and "{n}" by the count of plural messages.
+type Bundle struct {
+	fallback string
+	storage  Storage
+	plurals  map[string]PluralRule
+	logger   Logger
+
+	mu       sync.RWMutex
+	catalogs map[string]map[string]message
+}
+
+// BundleOption configures Bundle
+type BundleOption func(*Bundle)
+
+// WithLanguageStorage stores languages chosen by users with SetUserLanguage in storage
+func WithLanguageStorage(storage Storage) BundleOption {
+	return func(b *Bundle) {
+		b.storage = storage
+	}
+}
+
+// WithPluralRule sets plural rule of lang, rules of "en" and "id" are built in.
+// Languages without a rule use the rule of English.
+func WithPluralRule(lang string, rule PluralRule) BundleOption {
+	return func(b *Bundle) {
+		b.plurals[normalizeLanguage(lang)] = rule
+	}
+}
+
+// WithBundleLogger sets logger reporting missing messages
+func WithBundleLogger(logger Logger) BundleOption {
+	return func(b *Bundle) {
+		b.logger = logger
+	}
+}
+
+// NewBundle creates empty Bundle, messages missing in the language of a
+// user are taken from fallback language
+func NewBundle(fallback string, opts ...BundleOption) *Bundle {
+	b := &Bundle{
+		fallback: normalizeLanguage(fallback),
+		plurals:  map[string]PluralRule{},
+		logger:   nopLogger{},
+		catalogs: map[string]map[string]message{},
+	}
+	for lang, rule := range defaultPluralRules {
+		b.plurals[lang] = rule
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// LoadJSON adds messages of lang from JSON catalog data
+func (b *Bundle) LoadJSON(lang string, data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("tbot: catalog %s: %v", lang, err)
+	}
+	messages := map[string]message{}
+	if err := flattenCatalog("", raw, messages); err != nil {
+		return fmt.Errorf("tbot: catalog %s: %v", lang, err)
+	}
+
+	lang = normalizeLanguage(lang)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	catalog := b.catalogs[lang]
+	if catalog == nil {
+		catalog = map[string]message{}
+		b.catalogs[lang] = catalog
+	}
+	for key, msg := range messages {
+		catalog[key] = msg
+	}
+	return nil
+}
+
+func flattenCatalog(prefix string, raw map[string]json.RawMessage, messages map[string]message) error {
+	for key, value := range raw {
+		var text string
+		if err := json.Unmarshal(value, &text); err == nil {
+			messages[prefix+key] = message{text: text}
+			continue
+		}
+		var nested map[string]json.RawMessage
+		if err := json.Unmarshal(value, &nested); err != nil {
+			return fmt.Errorf("key %s%s: value is neither text nor object", prefix, key)
+		}
+		if isPluralObject(nested) {
+			forms := map[string]string{}
+			for form, v := range nested {
+				var text string
+				if err := json.Unmarshal(v, &text); err != nil {
+					return fmt.Errorf("key %s%s: plural form %s is not text", prefix, key, form)
+				}
+				forms[form] = text
+			}
+			messages[prefix+key] = message{forms: forms}
+			continue
+		}
+		if err := flattenCatalog(prefix+key+".", nested, messages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isPluralObject(obj map[string]json.RawMessage) bool {
+	if _, ok := obj["other"]; !ok {
+		return false
+	}
+	for key := range obj {
+		if !pluralCategories[key] {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadFile adds messages of lang from JSON catalog file
+func (b *Bundle) LoadFile(lang, filename string) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return b.LoadJSON(lang, data)
+}
+
+// LoadFS loads every "<lang>.json" catalog in dir of fsys, it works with
+// embed.FS as well as with os.DirFS
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		if err := b.LoadJSON(strings.TrimSuffix(path.Base(file), ".json"), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadDir loads every "<lang>.json" catalog in directory dir
+func (b *Bundle) LoadDir(dir string) error {
+	return b.LoadFS(os.DirFS(dir), ".")
+}
+
+// Languages returns loaded languages in alphabetical order
+func (b *Bundle) Languages() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	langs := make([]string, 0, len(b.catalogs))
+	for lang := range b.catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+func normalizeLanguage(lang string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "_", "-"))
+}
+
+// Localizer returns Localizer of lang. A regional language such as "en-GB"
+// falls back to its base language "en", then to the fallback of the bundle.
+func (b *Bundle) Localizer(lang string) *Localizer {
+	lang = normalizeLanguage(lang)
+	chain := []string{lang}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		chain = append(chain, base)
+	}
+	chain = append(chain, b.fallback)
+
+	l := &Localizer{bundle: b}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, lang := range chain {
+		if catalog, ok := b.catalogs[lang]; ok {
+			if l.lang == "" {
+				l.lang = lang
+			}
+			l.catalogs = append(l.catalogs, catalog)
+		}
+	}
+	if l.lang == "" {
+		l.lang = b.fallback
+	}
+	l.plural = b.pluralRule(l.lang)
+	return l
+}
+
+func (b *Bundle) pluralRule(lang string) PluralRule {
+	if rule, ok := b.plurals[lang]; ok {
+		return rule
+	}
+	if base, _, ok := strings.Cut(lang, "-"); ok {
+		if rule, ok := b.plurals[base]; ok {
+			return rule
+		}
+	}
+	return defaultPluralRules["en"]
+}
+
+func languageKey(userID int) string {
+	return "tbot:lang:" + strconv.Itoa(userID)
+}
+
+// UserLanguage returns language chosen by user with SetUserLanguage, or the
+// language of the user's Telegram client, or the fallback language
+func (b *Bundle) UserLanguage(u *User) string {
+	if u == nil {
+		return b.fallback
+	}
+	if b.storage != nil {
+		lang, err := b.storage.Get(languageKey(u.ID))
+		switch {
+		case err == nil:
+			return string(lang)
+		case !errors.Is(err, ErrNotFound):
+			b.logger.Errorf("tbot: load language of user %d: %v", u.ID, err)
+		}
+	}
+	if u.LanguageCode != "" {
+		return normalizeLanguage(u.LanguageCode)
+	}
+	return b.fallback
+}
+
+// SetUserLanguage overrides language of user, empty lang restores the
+// language of the user's Telegram client. It requires WithLanguageStorage.
+func (b *Bundle) SetUserLanguage(userID int, lang string) error {
+	if b.storage == nil {
+		return errors.New("tbot: bundle has no language storage")
+	}
+	if lang == "" {
+		return b.storage.Delete(languageKey(userID))
+	}
+	return b.storage.Set(languageKey(userID), []byte(normalizeLanguage(lang)), 0)
+}
+
+// ForUser returns Localizer of the language of u
+func (b *Bundle) ForUser(u *User) *Localizer {
+	return b.Localizer(b.UserLanguage(u))
+}
+
+// Localizer translates messages to one language
+type Localizer struct {
+	bundle   *Bundle
+	lang     string
+	catalogs []map[string]message
+	plural   PluralRule
+}
+
+// Lang returns the language of the localizer
+func (l *Localizer) Lang() string {
+	return l.lang
+}
+
+func (l *Localizer) lookup(key string) (message, bool) {
+	for _, catalog := range l.catalogs {
+		if msg, ok := catalog[key]; ok {
+			return msg, true
+		}
+	}
+	if l.bundle != nil {
+		l.bundle.logger.Warnf("tbot: message %q is missing in %s", key, l.lang)
+	}
+	return message{}, false
+}
+
+// T returns message key with "{0}", "{1}"... replaced by args. Missing
+// messages are returned as their key.
+func (l *Localizer) T(key string, args ...any) string {
+	msg, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	text := msg.text
+	if msg.forms != nil {
+		text = msg.forms["other"]
+	}
+	return formatMessage(text, args, nil)
+}
+
+// TN returns plural message key in the form for count n, with "{n}"
+// replaced by n and "{0}", "{1}"... replaced by args
+func (l *Localizer) TN(key string, n int, args ...any) string {
+	msg, ok := l.lookup(key)
+	if !ok {
+		return key
+	}
+	text := msg.text
+	if msg.forms != nil {
+		var found bool
+		if text, found = msg.forms[l.plural(n)]; !found {
+			text = msg.forms["other"]
+		}
+	}
+	return formatMessage(text, args, &n)
+}
+
+func formatMessage(text string, args []any, n *int) string {
+	if !strings.Contains(text, "{") {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(args)+2)
+	for i, arg := range args {
+		pairs = append(pairs, "{"+strconv.Itoa(i)+"}", fmt.Sprint(arg))
+	}
+	if n != nil {
+		pairs = append(pairs, "{n}", strconv.Itoa(*n))
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// SyncCommands sets commands of the bot in every loaded language.
+// Description of each command is a message key, e.g. "command.start".
+// Commands in the fallback language are also set as the default for users
+// whose language has no catalog. Regional catalogs such as "pt-br" are set
+// for their base language. Options are passed to Client.SetMyCommands.
+func (b *Bundle) SyncCommands(client *Client, commands []BotCommand, opts ...sendOption) error {
+	localized := func(l *Localizer) []BotCommand {
+		result := make([]BotCommand, len(commands))
+		for i, cmd := range commands {
+			result[i] = BotCommand{Command: cmd.Command, Description: l.T(cmd.Description)}
+		}
+		return result
+	}
+	var errs []error
+	if err := client.SetMyCommands(localized(b.Localizer(b.fallback)), opts...); err != nil {
+		errs = append(errs, err)
+	}
+	langs := b.Languages()
+	for _, lang := range langs {
+		// Telegram accepts only two-letter ISO 639-1 codes, a regional
+		// catalog is used for its base language unless that one is loaded
+		code, _, regional := strings.Cut(lang, "-")
+		if i := sort.SearchStrings(langs, code); regional && i < len(langs) && langs[i] == code {
+			b.logger.Warnf("tbot: commands in %s are not set, %s is used for them", lang, code)
+			continue
+		}
+		langOpts := append(opts[:len(opts):len(opts)], OptCommandsLanguage(code))
+		if err := client.SetMyCommands(localized(b.Localizer(lang)), langOpts...); err != nil {
+			errs = append(errs, fmt.Errorf("tbot: set commands in %s: %w", lang, err))
+		}
+	}
+	return errors.Join(errs...)
+}
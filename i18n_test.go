@@ -0,0 +1,111 @@
+package tbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBundle_Localizer(t *testing.T) {
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{
+			"greeting": "Hello, {0}!",
+			"incidents": {"one": "{n} open incident", "other": "{n} open incidents"},
+			"command": {"start": "Start the bot"},
+			"only_en": "English only"
+		}`)},
+		"locales/id.json": {Data: []byte(`{
+			"greeting": "Halo, {0}!",
+			"incidents": {"other": "{n} insiden terbuka"},
+			"command": {"start": "Mulai bot"}
+		}`)},
+	}
+	storage := NewMemoryStorage()
+	b := NewBundle("en", WithLanguageStorage(storage))
+	if err := b.LoadFS(fsys, "locales"); err != nil {
+		t.Fatalf("LoadFS() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		user *User
+		got  func(l *Localizer) string
+		want string
+	}{
+		{name: "args", user: &User{ID: 1, LanguageCode: "id"}, got: func(l *Localizer) string { return l.T("greeting", "Budi") }, want: "Halo, Budi!"},
+		{name: "region", user: &User{ID: 1, LanguageCode: "en-GB"}, got: func(l *Localizer) string { return l.T("command.start") }, want: "Start the bot"},
+		{name: "plural one", user: &User{ID: 1, LanguageCode: "en"}, got: func(l *Localizer) string { return l.TN("incidents", 1) }, want: "1 open incident"},
+		{name: "plural other", user: &User{ID: 1, LanguageCode: "en"}, got: func(l *Localizer) string { return l.TN("incidents", 3) }, want: "3 open incidents"},
+		{name: "indonesian plural", user: &User{ID: 1, LanguageCode: "id"}, got: func(l *Localizer) string { return l.TN("incidents", 1) }, want: "1 insiden terbuka"},
+		{name: "fallback message", user: &User{ID: 1, LanguageCode: "id"}, got: func(l *Localizer) string { return l.T("only_en") }, want: "English only"},
+		{name: "fallback language", user: &User{ID: 1, LanguageCode: "fr"}, got: func(l *Localizer) string { return l.T("greeting", "Jean") }, want: "Hello, Jean!"},
+		{name: "missing", user: nil, got: func(l *Localizer) string { return l.T("nope") }, want: "nope"},
+		{name: "override", user: &User{ID: 2, LanguageCode: "en"}, got: func(l *Localizer) string { return l.T("greeting", "Ani") }, want: "Halo, Ani!"},
+	}
+	if err := b.SetUserLanguage(2, "id"); err != nil {
+		t.Fatalf("SetUserLanguage() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.got(b.ForUser(tt.user)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContext_TWithoutBundle(t *testing.T) {
+	router := NewRouter(NewClient("token", "http://localhost"))
+	var got []string
+	router.OnMessage(func(c *Context) error {
+		got = append(got, c.T("greeting", "Ani"), c.TN("items", 2), c.Localizer().Lang())
+		return nil
+	})
+	u := &Update{Message: &Message{From: &User{ID: 1, LanguageCode: "de"}, Text: "hi"}}
+	if err := router.HandleUpdate(context.Background(), u); err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	if want := []string{"greeting", "items", "de"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("translations = %q, want %q", got, want)
+	}
+}
+
+func TestBundle_SyncCommands(t *testing.T) {
+	var got []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		got = append(got, r.Form.Get("language_code")+" "+r.Form.Get("commands"))
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	b := NewBundle("en")
+	catalogs := map[string]string{
+		"en":    `{"start": "Start"}`,
+		"pt":    `{"start": "Iniciar"}`,
+		"pt-br": `{"start": "Começar"}`,
+		"es-mx": `{"start": "Empezar"}`,
+	}
+	for lang, data := range catalogs {
+		if err := b.LoadJSON(lang, []byte(data)); err != nil {
+			t.Fatalf("LoadJSON(%s) error = %v", lang, err)
+		}
+	}
+	commands := []BotCommand{{Command: "start", Description: "start"}}
+	if err := b.SyncCommands(NewClient("token", srv.URL), commands); err != nil {
+		t.Fatalf("SyncCommands() error = %v", err)
+	}
+	want := []string{
+		` [{"command":"start","description":"Start"}]`,
+		`en [{"command":"start","description":"Start"}]`,
+		`es [{"command":"start","description":"Empezar"}]`,
+		`pt [{"command":"start","description":"Iniciar"}]`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %q\nwant %q", got, want)
+	}
+}
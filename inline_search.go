@@ -0,0 +1,267 @@
+package tbot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InlineSource returns results of query starting at offset, and the offset of
+// the next page or empty string if there are no more results. The first page
+// is requested with empty offset. Offsets must not contain '#'.
+type InlineSource func(ctx context.Context, query, offset string) ([]InlineQueryResult, string, error)
+
+type inlinePage struct {
+	results []InlineQueryResult
+	next    string
+	expires time.Time
+}
+
+// InlineSearch answers inline queries from an InlineSource. It waits until the
+// user stops typing before querying the source, caches source pages per query
+// and splits pages longer than 50 results into several answers.
+type InlineSearch struct {
+	source    InlineSource
+	debounce  time.Duration
+	ttl       time.Duration
+	cacheTime int
+	personal  bool
+	onError   func(q *InlineQuery, err error)
+
+	// background answers are cancelled by Shutdown only
+	ctx     context.Context
+	cancel  context.CancelFunc
+	pending sync.WaitGroup
+
+	mu      sync.Mutex
+	pages   map[string]*inlinePage
+	latest  map[int]uint64
+	counter uint64
+}
+
+// InlineSearchOption configures InlineSearch
+type InlineSearchOption func(*InlineSearch)
+
+// WithDebounce sets how long a query must stay unchanged before it is
+// answered, defaults to 300ms. Zero disables debouncing.
+func WithDebounce(d time.Duration) InlineSearchOption {
+	return func(s *InlineSearch) {
+		s.debounce = d
+	}
+}
+
+// WithResultTTL sets how long source pages are cached, defaults to 1 minute.
+// Zero disables caching.
+func WithResultTTL(d time.Duration) InlineSearchOption {
+	return func(s *InlineSearch) {
+		s.ttl = d
+	}
+}
+
+// WithInlineCacheTime sets cache_time of answers, i.e. how long Telegram
+// caches them on its side
+func WithInlineCacheTime(seconds int) InlineSearchOption {
+	return func(s *InlineSearch) {
+		s.cacheTime = seconds
+	}
+}
+
+// WithPersonalResults marks answers as personal, so Telegram does not share
+// its cache between users. The local cache is then kept per user as well.
+func WithPersonalResults() InlineSearchOption {
+	return func(s *InlineSearch) {
+		s.personal = true
+	}
+}
+
+// WithInlineErrorHandler sets function called with errors of answers sent in
+// background after debouncing, by default they are logged by the client logger
+func WithInlineErrorHandler(fn func(q *InlineQuery, err error)) InlineSearchOption {
+	return func(s *InlineSearch) {
+		s.onError = fn
+	}
+}
+
+// NewInlineSearch creates InlineSearch answering from source
+func NewInlineSearch(source InlineSource, opts ...InlineSearchOption) *InlineSearch {
+	s := &InlineSearch{
+		source:    source,
+		debounce:  300 * time.Millisecond,
+		ttl:       time.Minute,
+		cacheTime: -1,
+		pages:     map[string]*inlinePage{},
+		latest:    map[int]uint64{},
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// inlineContext has values of the router context, but is cancelled only
+// when InlineSearch is shut down
+type inlineContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c inlineContext) Value(key any) any {
+	return c.values.Value(key)
+}
+
+// Handle answers inline query of the update, it is meant to be registered
+// with Router.OnInlineQuery. With debouncing the answer is sent in background
+// and the query is dropped if the same user sends a newer one meanwhile.
+// Background answers outlive the router context, see Shutdown.
+func (s *InlineSearch) Handle(c *Context) error {
+	iq := c.InlineQuery()
+	if iq == nil {
+		return nil
+	}
+	if s.debounce <= 0 || iq.Offset != "" {
+		// next pages are requested by scrolling, not typing
+		return s.answer(c, c.Client, iq)
+	}
+
+	userID := 0
+	if iq.From != nil {
+		userID = iq.From.ID
+	}
+	s.mu.Lock()
+	s.counter++
+	generation := s.counter
+	s.latest[userID] = generation
+	s.pending.Add(1)
+	s.mu.Unlock()
+
+	ctx, client := inlineContext{Context: s.ctx, values: c.Context}, c.Client
+	go func() {
+		defer s.pending.Done()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.debounce):
+		}
+		s.mu.Lock()
+		current := s.latest[userID] == generation
+		if current {
+			delete(s.latest, userID)
+		}
+		s.mu.Unlock()
+		if !current {
+			return
+		}
+		if err := s.answer(ctx, client, iq); err != nil {
+			if s.onError != nil {
+				s.onError(iq, err)
+				return
+			}
+			client.logger.Errorf("tbot: unable to answer inline query %q: %v", iq.Query, err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown waits until answers pending in background are sent. If ctx is
+// done first, they are cancelled and ctx.Err() is returned. It is meant to be
+// passed to WithShutdownHook, so it runs after handlers returned.
+func (s *InlineSearch) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.pending.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (s *InlineSearch) answer(ctx context.Context, client *Client, iq *InlineQuery) error {
+	sourceOffset, skip := parseInlineOffset(iq.Offset)
+	page, err := s.page(ctx, iq, sourceOffset)
+	if err != nil {
+		return err
+	}
+
+	results := page.results
+	if skip > len(results) {
+		skip = len(results)
+	}
+	results = results[skip:]
+	next := page.next
+	if len(results) > MaxInlineQueryResults {
+		results = results[:MaxInlineQueryResults]
+		next = sourceOffset + "#" + strconv.Itoa(skip+MaxInlineQueryResults)
+	}
+
+	opts := []sendOption{OptNextOffset(next)}
+	if s.cacheTime >= 0 {
+		opts = append(opts, OptCacheTime(s.cacheTime))
+	}
+	if s.personal {
+		opts = append(opts, OptIsPersonal)
+	}
+	return client.AnswerInlineQuery(iq.ID, results, opts...)
+}
+
+func (s *InlineSearch) page(ctx context.Context, iq *InlineQuery, offset string) (*inlinePage, error) {
+	key := iq.Query + "\x00" + offset
+	if s.personal && iq.From != nil {
+		key = strconv.Itoa(iq.From.ID) + "\x00" + key
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	page, ok := s.pages[key]
+	if ok && now.After(page.expires) {
+		delete(s.pages, key)
+		ok = false
+	}
+	s.mu.Unlock()
+	if ok {
+		return page, nil
+	}
+
+	results, next, err := s.source(ctx, iq.Query, offset)
+	if err != nil {
+		return nil, err
+	}
+	page = &inlinePage{results: results, next: next, expires: now.Add(s.ttl)}
+	if s.ttl > 0 {
+		s.mu.Lock()
+		s.evictExpired(now)
+		s.pages[key] = page
+		s.mu.Unlock()
+	}
+	return page, nil
+}
+
+func (s *InlineSearch) evictExpired(now time.Time) {
+	for key, page := range s.pages {
+		if now.After(page.expires) {
+			delete(s.pages, key)
+		}
+	}
+}
+
+// parseInlineOffset splits offset sent to Telegram into source offset and
+// the number of results of that source page which were already shown
+func parseInlineOffset(offset string) (string, int) {
+	i := strings.LastIndex(offset, "#")
+	if i < 0 {
+		return offset, 0
+	}
+	skip, err := strconv.Atoi(offset[i+1:])
+	if err != nil || skip < 0 {
+		return offset, 0
+	}
+	return offset[:i], skip
+}
@@ -0,0 +1,217 @@
+package tbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type inlineAnswer struct {
+	query   string
+	results int
+	next    string
+}
+
+func inlineSearchServer(t *testing.T) (*httptest.Server, chan inlineAnswer) {
+	answers := make(chan inlineAnswer, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		var results []json.RawMessage
+		if err := json.Unmarshal([]byte(r.Form.Get("results")), &results); err != nil {
+			t.Errorf("results = %s is not a JSON array", r.Form.Get("results"))
+		}
+		answers <- inlineAnswer{query: r.Form.Get("inline_query_id"), results: len(results), next: r.Form.Get("next_offset")}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	return srv, answers
+}
+
+func TestInlineSearch_Paging(t *testing.T) {
+	srv, answers := inlineSearchServer(t)
+	defer srv.Close()
+
+	var calls int32
+	source := func(ctx context.Context, query, offset string) ([]InlineQueryResult, string, error) {
+		atomic.AddInt32(&calls, 1)
+		n, next := 120, "p2"
+		if offset == "p2" {
+			n, next = 3, ""
+		}
+		results := make([]InlineQueryResult, n)
+		for i := range results {
+			results[i] = InlineQueryResultGame{ID: offset + strconv.Itoa(i)}
+		}
+		return results, next, nil
+	}
+	router := NewRouter(NewClient("token", srv.URL))
+	router.OnInlineQuery(NewInlineSearch(source, WithDebounce(0)).Handle)
+
+	tests := []struct {
+		offset string
+		want   inlineAnswer
+	}{
+		{offset: "", want: inlineAnswer{query: "1", results: 50, next: "#50"}},
+		{offset: "#50", want: inlineAnswer{query: "2", results: 50, next: "#100"}},
+		{offset: "#100", want: inlineAnswer{query: "3", results: 20, next: "p2"}},
+		{offset: "p2", want: inlineAnswer{query: "4", results: 3, next: ""}},
+		{offset: "#500", want: inlineAnswer{query: "5", results: 0, next: "p2"}},
+	}
+	for i, tt := range tests {
+		u := &Update{InlineQuery: &InlineQuery{ID: strconv.Itoa(i + 1), From: &User{ID: 1}, Query: "go", Offset: tt.offset}}
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate(%q) error = %v", tt.offset, err)
+		}
+		if got := <-answers; got != tt.want {
+			t.Errorf("answer to offset %q = %+v, want %+v", tt.offset, got, tt.want)
+		}
+	}
+	// the first source page is cached while it is shown in parts
+	if calls != 2 {
+		t.Errorf("source calls = %d, want 2", calls)
+	}
+}
+
+func TestInlineSearch_Debounce(t *testing.T) {
+	srv, answers := inlineSearchServer(t)
+	defer srv.Close()
+
+	var queries []string
+	source := func(ctx context.Context, query, offset string) ([]InlineQueryResult, string, error) {
+		queries = append(queries, query)
+		return []InlineQueryResult{InlineQueryResultGame{ID: query}}, "", nil
+	}
+	router := NewRouter(NewClient("token", srv.URL))
+	router.OnInlineQuery(NewInlineSearch(source, WithDebounce(30*time.Millisecond)).Handle)
+
+	for i, query := range []string{"g", "go", "gol"} {
+		u := &Update{InlineQuery: &InlineQuery{ID: strconv.Itoa(i + 1), From: &User{ID: 1}, Query: query}}
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate() error = %v", err)
+		}
+	}
+	select {
+	case got := <-answers:
+		if got.query != "3" {
+			t.Errorf("answered query %s, want only the last one", got.query)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("debounced query was not answered")
+	}
+	select {
+	case got := <-answers:
+		t.Errorf("unexpected answer %+v", got)
+	case <-time.After(60 * time.Millisecond):
+	}
+	if len(queries) != 1 || queries[0] != "gol" {
+		t.Errorf("source queries = %q, want [gol]", queries)
+	}
+}
+
+func TestInlineSearch_ErrorHandler(t *testing.T) {
+	errs := make(chan error, 1)
+	source := func(ctx context.Context, query, offset string) ([]InlineQueryResult, string, error) {
+		return nil, "", fmt.Errorf("source is down")
+	}
+	search := NewInlineSearch(source, WithDebounce(time.Millisecond), WithInlineErrorHandler(func(q *InlineQuery, err error) {
+		errs <- err
+	}))
+	router := NewRouter(NewClient("token", "http://localhost"))
+	router.OnInlineQuery(search.Handle)
+	u := &Update{InlineQuery: &InlineQuery{ID: "1", From: &User{ID: 1}, Query: "go"}}
+	if err := router.HandleUpdate(context.Background(), u); err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	select {
+	case err := <-errs:
+		if err.Error() != "source is down" {
+			t.Errorf("error = %v, want source error", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("error handler was not called")
+	}
+}
+
+func TestInlineSearch_Shutdown(t *testing.T) {
+	srv, answers := inlineSearchServer(t)
+	defer srv.Close()
+
+	type key struct{}
+	values := make(chan any, 2)
+	source := func(ctx context.Context, query, offset string) ([]InlineQueryResult, string, error) {
+		values <- ctx.Value(key{})
+		<-ctx.Done()
+		return nil, "", ctx.Err()
+	}
+	errs := make(chan error, 1)
+	search := NewInlineSearch(source, WithDebounce(10*time.Millisecond), WithInlineErrorHandler(func(q *InlineQuery, err error) {
+		errs <- err
+	}))
+	router := NewRouter(NewClient("token", srv.URL))
+	router.OnInlineQuery(search.Handle)
+
+	// the context of the update is cancelled as soon as the handler returns
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "value"))
+	u := &Update{InlineQuery: &InlineQuery{ID: "1", From: &User{ID: 1}, Query: "go"}}
+	if err := router.HandleUpdate(ctx, u); err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	cancel()
+	select {
+	case v := <-values:
+		if v != "value" {
+			t.Errorf("context value = %v, want the value of the update context", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("source was not queried after the update context was cancelled")
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShutdown()
+	if err := search.Shutdown(shutdownCtx); err != context.DeadlineExceeded {
+		t.Errorf("Shutdown() error = %v, want deadline exceeded", err)
+	}
+	select {
+	case err := <-errs:
+		if err != context.Canceled {
+			t.Errorf("error of the cancelled answer = %v", err)
+		}
+	default:
+		t.Error("Shutdown() returned before the pending answer finished")
+	}
+	if len(answers) != 0 {
+		t.Errorf("unexpected answer %+v", <-answers)
+	}
+}
+
+func TestInlineSearch_ShutdownWaits(t *testing.T) {
+	srv, answers := inlineSearchServer(t)
+	defer srv.Close()
+
+	source := func(ctx context.Context, query, offset string) ([]InlineQueryResult, string, error) {
+		return []InlineQueryResult{InlineQueryResultGame{ID: query}}, "", nil
+	}
+	search := NewInlineSearch(source, WithDebounce(30*time.Millisecond))
+	router := NewRouter(NewClient("token", srv.URL))
+	router.OnInlineQuery(search.Handle)
+	u := &Update{InlineQuery: &InlineQuery{ID: "1", From: &User{ID: 1}, Query: "go"}}
+	if err := router.HandleUpdate(context.Background(), u); err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	if err := search.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	select {
+	case got := <-answers:
+		if got.query != "1" {
+			t.Errorf("answered query %s, want 1", got.query)
+		}
+	default:
+		t.Error("Shutdown() returned before the debounced query was answered")
+	}
+}
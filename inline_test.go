@@ -0,0 +1,160 @@
+package tbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalInlineQueryResults(t *testing.T) {
+	tests := []struct {
+		result InlineQueryResult
+		want   string
+	}{
+		{
+			result: InlineQueryResultArticle{ID: "1", Title: "Go", InputMessageContent: InputTextMessageContent{MessageText: "golang", ParseMode: "HTML"}},
+			want:   `{"type":"article","id":"1","title":"Go","input_message_content":{"message_text":"golang","parse_mode":"HTML"}}`,
+		},
+		{
+			result: &InlineQueryResultPhoto{ID: "2", PhotoURL: "https://x/p.jpg", ThumbnailURL: "https://x/t.jpg"},
+			want:   `{"type":"photo","id":"2","photo_url":"https://x/p.jpg","thumbnail_url":"https://x/t.jpg"}`,
+		},
+		{
+			result: InlineQueryResultLocation{ID: "3", Latitude: 1.5, Longitude: -2, Title: "Here", InputMessageContent: InputLocationMessageContent{Latitude: 1.5, Longitude: -2}},
+			want:   `{"type":"location","id":"3","latitude":1.5,"longitude":-2,"title":"Here","input_message_content":{"latitude":1.5,"longitude":-2}}`,
+		},
+		{
+			result: InlineQueryResultContact{ID: "4", PhoneNumber: "+1", FirstName: "Ani"},
+			want:   `{"type":"contact","id":"4","phone_number":"+1","first_name":"Ani"}`,
+		},
+		{
+			result: InlineQueryResultGame{ID: "5", GameShortName: "tetris"},
+			want:   `{"type":"game","id":"5","game_short_name":"tetris"}`,
+		},
+		{
+			result: InlineQueryResultCachedSticker{ID: "6", StickerFileID: "s"},
+			want:   `{"type":"sticker","id":"6","sticker_file_id":"s"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.result.inlineQueryResultType(), func(t *testing.T) {
+			got, err := marshalInlineQueryResults([]InlineQueryResult{tt.result})
+			if err != nil {
+				t.Fatalf("marshalInlineQueryResults() error = %v", err)
+			}
+			if want := "[" + tt.want + "]"; got != want {
+				t.Errorf("marshalInlineQueryResults() = %s\nwant %s", got, want)
+			}
+		})
+	}
+
+	types := []struct {
+		result InlineQueryResult
+		want   string
+	}{
+		{InlineQueryResultGif{}, "gif"},
+		{InlineQueryResultMpeg4Gif{}, "mpeg4_gif"},
+		{InlineQueryResultVideo{}, "video"},
+		{InlineQueryResultAudio{}, "audio"},
+		{InlineQueryResultVoice{}, "voice"},
+		{InlineQueryResultDocument{}, "document"},
+		{InlineQueryResultVenue{}, "venue"},
+		{InlineQueryResultCachedPhoto{}, "photo"},
+		{InlineQueryResultCachedGif{}, "gif"},
+		{InlineQueryResultCachedMpeg4Gif{}, "mpeg4_gif"},
+		{InlineQueryResultCachedDocument{}, "document"},
+		{InlineQueryResultCachedVideo{}, "video"},
+		{InlineQueryResultCachedVoice{}, "voice"},
+		{InlineQueryResultCachedAudio{}, "audio"},
+	}
+	for _, tt := range types {
+		encoded, err := marshalInlineQueryResults([]InlineQueryResult{tt.result})
+		if err != nil {
+			t.Fatalf("marshalInlineQueryResults(%T) error = %v", tt.result, err)
+		}
+		var decoded []map[string]any
+		if err := json.Unmarshal([]byte(encoded), &decoded); err != nil {
+			t.Fatalf("marshalInlineQueryResults(%T) = invalid JSON %s", tt.result, encoded)
+		}
+		if decoded[0]["type"] != tt.want {
+			t.Errorf("type of %T = %v, want %s", tt.result, decoded[0]["type"], tt.want)
+		}
+	}
+}
+
+func TestRouter_OnInlineQuery(t *testing.T) {
+	var form url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form = r.Form
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	router := NewRouter(NewClient("token", srv.URL))
+	var chosen string
+	router.OnInlineQuery(func(c *Context) error {
+		q := c.InlineQuery()
+		return c.AnswerInline([]InlineQueryResult{
+			InlineQueryResultArticle{ID: "a", Title: strings.ToUpper(q.Query), InputMessageContent: InputTextMessageContent{MessageText: q.Query}},
+		}, OptIsPersonal, OptNextOffset("10"))
+	})
+	router.OnChosenInlineResult(func(c *Context) error {
+		chosen = c.Update.ChosenInlineResult.ResultID
+		return nil
+	})
+
+	updates := []*Update{
+		{InlineQuery: &InlineQuery{ID: "q1", From: &User{ID: 1}, Query: "go"}},
+		{ChosenInlineResult: &ChosenInlineResult{ResultID: "a", From: &User{ID: 1}}},
+	}
+	for _, u := range updates {
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate() error = %v", err)
+		}
+	}
+	want := map[string]string{
+		"inline_query_id": "q1",
+		"results":         `[{"type":"article","id":"a","title":"GO","input_message_content":{"message_text":"go"}}]`,
+		"is_personal":     "true",
+		"next_offset":     "10",
+	}
+	for key, value := range want {
+		if got := form.Get(key); got != value {
+			t.Errorf("%s = %s, want %s", key, got, value)
+		}
+	}
+	if chosen != "a" {
+		t.Errorf("chosen result = %q, want %q", chosen, "a")
+	}
+
+	tooMany := make([]InlineQueryResult, MaxInlineQueryResults+1)
+	for i := range tooMany {
+		tooMany[i] = InlineQueryResultGame{ID: fmt.Sprint(i)}
+	}
+	if err := NewClient("token", srv.URL).AnswerInlineQuery("q", tooMany); err == nil {
+		t.Error("AnswerInlineQuery() accepted more than 50 results")
+	}
+}
+
+func TestInlineQueryResultPointer(t *testing.T) {
+	encoded, err := marshalInlineQueryResults([]InlineQueryResult{&InlineQueryResultGame{ID: "g"}, InlineQueryResultGame{ID: "h"}})
+	if err != nil {
+		t.Fatalf("marshalInlineQueryResults() error = %v", err)
+	}
+	var decoded []map[string]any
+	_ = json.Unmarshal([]byte(encoded), &decoded)
+	want := []map[string]any{
+		{"type": "game", "id": "g", "game_short_name": ""},
+		{"type": "game", "id": "h", "game_short_name": ""},
+	}
+	if !reflect.DeepEqual(decoded, want) {
+		t.Errorf("marshalInlineQueryResults() = %v, want %v", decoded, want)
+	}
+}
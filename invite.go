@@ -0,0 +1,126 @@
+package tbot
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ChatInviteLink represents an invite link for a chat
+type ChatInviteLink struct {
+	InviteLink string `json:"invite_link"`
+	Creator    *User  `json:"creator"`
+	// CreatesJoinRequest True, if users joining via the link need to be approved by administrators
+	CreatesJoinRequest bool   `json:"creates_join_request"`
+	IsPrimary          bool   `json:"is_primary"`
+	IsRevoked          bool   `json:"is_revoked"`
+	Name               string `json:"name,omitempty"`
+	// ExpireDate is unix time when the link expires, 0 if it does not
+	ExpireDate int64 `json:"expire_date,omitempty"`
+	// MemberLimit is maximum number of users that can be members of the chat
+	// simultaneously after joining via the link, 0 if unlimited
+	MemberLimit             int `json:"member_limit,omitempty"`
+	PendingJoinRequestCount int `json:"pending_join_request_count,omitempty"`
+}
+
+// ChatJoinRequest represents a request to join a chat
+type ChatJoinRequest struct {
+	Chat Chat  `json:"chat"`
+	From *User `json:"from"`
+	// UserChatID is ID of the private chat with the user, the bot can message
+	// the user there until the request is processed
+	UserChatID int             `json:"user_chat_id"`
+	Date       int64           `json:"date"`
+	Bio        string          `json:"bio,omitempty"`
+	InviteLink *ChatInviteLink `json:"invite_link,omitempty"`
+}
+
+var (
+	OptInviteLinkName = func(name string) sendOption {
+		return func(r url.Values) {
+			r.Set("name", name)
+		}
+	}
+	OptInviteLinkExpireDate = func(t time.Time) sendOption {
+		return func(r url.Values) {
+			r.Set("expire_date", strconv.FormatInt(t.Unix(), 10))
+		}
+	}
+	OptInviteLinkMemberLimit = func(n int) sendOption {
+		return func(r url.Values) {
+			r.Set("member_limit", strconv.Itoa(n))
+		}
+	}
+	// OptCreatesJoinRequest makes users joining via the link wait for approval,
+	// it cannot be used together with OptInviteLinkMemberLimit
+	OptCreatesJoinRequest = func(r url.Values) { r.Set("creates_join_request", "true") }
+)
+
+// ExportChatInviteLink generates new primary invite link of the chat,
+// previous primary link is revoked
+func (c *Client) ExportChatInviteLink(chatID string) (string, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	var link string
+	err := c.sendRequest("/exportChatInviteLink", req, &link)
+	return link, err
+}
+
+// CreateChatInviteLink creates additional invite link of the chat. Available options:
+//   - OptInviteLinkName(name string)
+//   - OptInviteLinkExpireDate(t time.Time)
+//   - OptInviteLinkMemberLimit(n int)
+//   - OptCreatesJoinRequest
+func (c *Client) CreateChatInviteLink(chatID string, opts ...sendOption) (*ChatInviteLink, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	for _, opt := range opts {
+		opt(req)
+	}
+	link := &ChatInviteLink{}
+	err := c.sendRequest("/createChatInviteLink", req, link)
+	return link, err
+}
+
+// EditChatInviteLink edits a non-primary invite link created by the bot.
+// Available options are the same as for CreateChatInviteLink.
+func (c *Client) EditChatInviteLink(chatID, inviteLink string, opts ...sendOption) (*ChatInviteLink, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("invite_link", inviteLink)
+	for _, opt := range opts {
+		opt(req)
+	}
+	link := &ChatInviteLink{}
+	err := c.sendRequest("/editChatInviteLink", req, link)
+	return link, err
+}
+
+// RevokeChatInviteLink revokes an invite link created by the bot. Revoking
+// the primary link generates a new one.
+func (c *Client) RevokeChatInviteLink(chatID, inviteLink string) (*ChatInviteLink, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("invite_link", inviteLink)
+	link := &ChatInviteLink{}
+	err := c.sendRequest("/revokeChatInviteLink", req, link)
+	return link, err
+}
+
+// ApproveChatJoinRequest approves request of user to join the chat
+func (c *Client) ApproveChatJoinRequest(chatID string, userID int) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("user_id", strconv.Itoa(userID))
+	var approved bool
+	return c.sendRequest("/approveChatJoinRequest", req, &approved)
+}
+
+// DeclineChatJoinRequest declines request of user to join the chat
+func (c *Client) DeclineChatJoinRequest(chatID string, userID int) error {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	req.Set("user_id", strconv.Itoa(userID))
+	var declined bool
+	return c.sendRequest("/declineChatJoinRequest", req, &declined)
+}
@@ -0,0 +1,92 @@
+package tbot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_ChatInviteLinks(t *testing.T) {
+	var form url.Values
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		form, path = r.Form, r.URL.Path
+		if path == "/bottoken/exportChatInviteLink" {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":"https://t.me/+primary"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":{"invite_link":"https://t.me/+abc","creator":{"id":100,"is_bot":true,"first_name":"Bot"},
+			"creates_join_request":true,"is_primary":false,"is_revoked":false,"name":"Promo","expire_date":1700000000,"pending_join_request_count":2}}`)
+	}))
+	defer srv.Close()
+
+	c := NewClient("token", srv.URL)
+	want := &ChatInviteLink{
+		InviteLink:              "https://t.me/+abc",
+		Creator:                 &User{ID: 100, IsBot: true, FirstName: "Bot"},
+		CreatesJoinRequest:      true,
+		Name:                    "Promo",
+		ExpireDate:              1700000000,
+		PendingJoinRequestCount: 2,
+	}
+	expire := time.Unix(1700000000, 0)
+	tests := []struct {
+		name string
+		call func() (*ChatInviteLink, error)
+		path string
+		form url.Values
+	}{
+		{
+			name: "create",
+			call: func() (*ChatInviteLink, error) {
+				return c.CreateChatInviteLink("-5", OptInviteLinkName("Promo"), OptInviteLinkExpireDate(expire), OptCreatesJoinRequest)
+			},
+			path: "/bottoken/createChatInviteLink",
+			form: url.Values{"chat_id": {"-5"}, "name": {"Promo"}, "expire_date": {"1700000000"}, "creates_join_request": {"true"}},
+		},
+		{
+			name: "edit",
+			call: func() (*ChatInviteLink, error) {
+				return c.EditChatInviteLink("-5", "https://t.me/+abc", OptInviteLinkMemberLimit(10))
+			},
+			path: "/bottoken/editChatInviteLink",
+			form: url.Values{"chat_id": {"-5"}, "invite_link": {"https://t.me/+abc"}, "member_limit": {"10"}},
+		},
+		{
+			name: "revoke",
+			call: func() (*ChatInviteLink, error) {
+				return c.RevokeChatInviteLink("-5", "https://t.me/+abc")
+			},
+			path: "/bottoken/revokeChatInviteLink",
+			form: url.Values{"chat_id": {"-5"}, "invite_link": {"https://t.me/+abc"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form, path = nil, ""
+			link, err := tt.call()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if path != tt.path || !reflect.DeepEqual(form, tt.form) {
+				t.Errorf("request %s %v, want %s %v", path, form, tt.path, tt.form)
+			}
+			if !reflect.DeepEqual(link, want) {
+				t.Errorf("link = %+v, want %+v", link, want)
+			}
+		})
+	}
+
+	link, err := c.ExportChatInviteLink("-5")
+	if err != nil || link != "https://t.me/+primary" {
+		t.Errorf("ExportChatInviteLink() = %q, %v", link, err)
+	}
+	if want := (url.Values{"chat_id": {"-5"}}); path != "/bottoken/exportChatInviteLink" || !reflect.DeepEqual(form, want) {
+		t.Errorf("request %s %v, want /bottoken/exportChatInviteLink %v", path, form, want)
+	}
+}
@@ -0,0 +1,314 @@
+package tbot
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Keyboard limits checked by keyboard builders
+const (
+	MaxInlineKeyboardColumns = 8
+	MaxInlineKeyboardButtons = 100
+	MaxReplyKeyboardColumns  = 12
+)
+
+// InlineURL creates inline button opening url
+func InlineURL(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, URL: url}
+}
+
+// InlineCallback creates inline button sending callback query with data
+func InlineCallback(text, data string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, CallbackData: data}
+}
+
+// InlineWebApp creates inline button launching Web App at url
+func InlineWebApp(text, url string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// InlineLogin creates inline button authorizing the user with Telegram Login
+func InlineLogin(text string, login *LoginURL) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, LoginURL: login}
+}
+
+// InlineSwitch creates inline button prompting the user to select a chat
+// and inserting bot's username and query into its input field
+func InlineSwitch(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQuery: &query}
+}
+
+// InlineSwitchCurrentChat creates inline button inserting bot's username and
+// query into the input field of the current chat
+func InlineSwitchCurrentChat(text, query string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryCurrentChat: &query}
+}
+
+// InlineSwitchChosenChat creates inline button switching to inline mode in a chat of chosen type
+func InlineSwitchChosenChat(text string, chosen *SwitchInlineQueryChosenChat) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, SwitchInlineQueryChosenChat: chosen}
+}
+
+// InlinePay creates pay button, it must be the first button of an invoice keyboard
+func InlinePay(text string) InlineKeyboardButton {
+	return InlineKeyboardButton{Text: text, Pay: true}
+}
+
+// ReplyText creates reply button sending its text
+func ReplyText(text string) KeyboardButton {
+	return KeyboardButton{Text: text}
+}
+
+// ReplyContact creates reply button sending user's phone number
+func ReplyContact(text string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestContact: true}
+}
+
+// ReplyLocation creates reply button sending user's location
+func ReplyLocation(text string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestLocation: true}
+}
+
+// ReplyPoll creates reply button asking the user to create a poll of pollType
+// ("quiz", "regular" or empty for any type)
+func ReplyPoll(text, pollType string) KeyboardButton {
+	return KeyboardButton{Text: text, RequestPoll: &KeyboardButtonPollType{Type: pollType}}
+}
+
+// ReplyUsers creates reply button asking the user to share users matching request
+func ReplyUsers(text string, request *KeyboardButtonRequestUsers) KeyboardButton {
+	return KeyboardButton{Text: text, RequestUsers: request}
+}
+
+// ReplyChat creates reply button asking the user to share a chat matching request
+func ReplyChat(text string, request *KeyboardButtonRequestChat) KeyboardButton {
+	return KeyboardButton{Text: text, RequestChat: request}
+}
+
+// ReplyWebApp creates reply button launching Web App at url
+func ReplyWebApp(text, url string) KeyboardButton {
+	return KeyboardButton{Text: text, WebApp: &WebAppInfo{URL: url}}
+}
+
+// InlineKeyboard builds InlineKeyboardMarkup row by row
+//
+//	markup, err := tbot.NewInlineKeyboard().Columns(3).
+//		Add(tbot.InlineCallback("1", "n:1"), tbot.InlineCallback("2", "n:2")).
+//		Row().
+//		Add(tbot.InlineURL("Docs", "https://core.telegram.org/bots/api")).
+//		Build()
+type InlineKeyboard struct {
+	rows    [][]InlineKeyboardButton
+	columns int
+}
+
+// NewInlineKeyboard creates empty InlineKeyboard
+func NewInlineKeyboard() *InlineKeyboard {
+	return &InlineKeyboard{}
+}
+
+// Columns makes Add start a new row when the current one has n buttons.
+// Zero disables wrapping.
+func (k *InlineKeyboard) Columns(n int) *InlineKeyboard {
+	k.columns = n
+	return k
+}
+
+// Row starts a new row
+func (k *InlineKeyboard) Row() *InlineKeyboard {
+	if len(k.rows) == 0 || len(k.rows[len(k.rows)-1]) > 0 {
+		k.rows = append(k.rows, nil)
+	}
+	return k
+}
+
+// Add appends buttons to the current row
+func (k *InlineKeyboard) Add(buttons ...InlineKeyboardButton) *InlineKeyboard {
+	for _, button := range buttons {
+		last := len(k.rows) - 1
+		if last < 0 || (k.columns > 0 && len(k.rows[last]) >= k.columns) {
+			k.rows = append(k.rows, nil)
+			last++
+		}
+		k.rows[last] = append(k.rows[last], button)
+	}
+	return k
+}
+
+// Build validates the keyboard and returns the markup
+func (k *InlineKeyboard) Build() (*InlineKeyboardMarkup, error) {
+	rows := make([][]InlineKeyboardButton, 0, len(k.rows))
+	total := 0
+	for i, row := range k.rows {
+		if len(row) == 0 {
+			continue
+		}
+		if len(row) > MaxInlineKeyboardColumns {
+			return nil, fmt.Errorf("tbot: inline keyboard row %d has %d buttons, max %d", i, len(row), MaxInlineKeyboardColumns)
+		}
+		for j, button := range row {
+			if err := validateInlineButton(button); err != nil {
+				return nil, fmt.Errorf("tbot: inline keyboard button %q: %v", button.Text, err)
+			}
+			if button.Pay && (len(rows) > 0 || j > 0) {
+				return nil, fmt.Errorf("tbot: inline keyboard button %q: pay button must be the first one", button.Text)
+			}
+		}
+		total += len(row)
+		rows = append(rows, row)
+	}
+	if total > MaxInlineKeyboardButtons {
+		return nil, fmt.Errorf("tbot: inline keyboard has %d buttons, max %d", total, MaxInlineKeyboardButtons)
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: rows}, nil
+}
+
+func validateInlineButton(b InlineKeyboardButton) error {
+	if b.Text == "" {
+		return errors.New("text is empty")
+	}
+	actions := 0
+	for _, set := range []bool{
+		b.URL != "",
+		b.CallbackData != "",
+		b.WebApp != nil,
+		b.LoginURL != nil,
+		b.SwitchInlineQuery != nil,
+		b.SwitchInlineQueryCurrentChat != nil,
+		b.SwitchInlineQueryChosenChat != nil,
+		b.CallbackGame != nil,
+		b.Pay,
+	} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return fmt.Errorf("exactly one action must be set, got %d", actions)
+	}
+	if len(b.CallbackData) > MaxCallbackDataLength {
+		return fmt.Errorf("callback data is %d bytes, max %d", len(b.CallbackData), MaxCallbackDataLength)
+	}
+	return nil
+}
+
+// ReplyKeyboard builds ReplyKeyboardMarkup row by row
+type ReplyKeyboard struct {
+	markup  ReplyKeyboardMarkup
+	columns int
+}
+
+// NewReplyKeyboard creates empty ReplyKeyboard
+func NewReplyKeyboard() *ReplyKeyboard {
+	return &ReplyKeyboard{}
+}
+
+// Columns makes Add start a new row when the current one has n buttons.
+// Zero disables wrapping.
+func (k *ReplyKeyboard) Columns(n int) *ReplyKeyboard {
+	k.columns = n
+	return k
+}
+
+// Row starts a new row
+func (k *ReplyKeyboard) Row() *ReplyKeyboard {
+	rows := k.markup.Keyboard
+	if len(rows) == 0 || len(rows[len(rows)-1]) > 0 {
+		k.markup.Keyboard = append(rows, nil)
+	}
+	return k
+}
+
+// Add appends buttons to the current row
+func (k *ReplyKeyboard) Add(buttons ...KeyboardButton) *ReplyKeyboard {
+	for _, button := range buttons {
+		last := len(k.markup.Keyboard) - 1
+		if last < 0 || (k.columns > 0 && len(k.markup.Keyboard[last]) >= k.columns) {
+			k.markup.Keyboard = append(k.markup.Keyboard, nil)
+			last++
+		}
+		k.markup.Keyboard[last] = append(k.markup.Keyboard[last], button)
+	}
+	return k
+}
+
+// Resize asks clients to fit the keyboard to its buttons
+func (k *ReplyKeyboard) Resize() *ReplyKeyboard {
+	k.markup.ResizeKeyboard = true
+	return k
+}
+
+// OneTime asks clients to hide the keyboard after it was used
+func (k *ReplyKeyboard) OneTime() *ReplyKeyboard {
+	k.markup.OneTimeKeyboard = true
+	return k
+}
+
+// Persistent asks clients to always show the keyboard
+func (k *ReplyKeyboard) Persistent() *ReplyKeyboard {
+	k.markup.IsPersistent = true
+	return k
+}
+
+// Selective shows the keyboard only to mentioned users and the sender of the replied message
+func (k *ReplyKeyboard) Selective() *ReplyKeyboard {
+	k.markup.Selective = true
+	return k
+}
+
+// Placeholder sets placeholder shown in the input field while the keyboard is active
+func (k *ReplyKeyboard) Placeholder(text string) *ReplyKeyboard {
+	k.markup.InputFieldPlaceholder = text
+	return k
+}
+
+// Build validates the keyboard and returns the markup
+func (k *ReplyKeyboard) Build() (*ReplyKeyboardMarkup, error) {
+	markup := k.markup
+	markup.Keyboard = make([][]KeyboardButton, 0, len(k.markup.Keyboard))
+	for i, row := range k.markup.Keyboard {
+		if len(row) == 0 {
+			continue
+		}
+		if len(row) > MaxReplyKeyboardColumns {
+			return nil, fmt.Errorf("tbot: reply keyboard row %d has %d buttons, max %d", i, len(row), MaxReplyKeyboardColumns)
+		}
+		for _, button := range row {
+			if err := validateReplyButton(button); err != nil {
+				return nil, fmt.Errorf("tbot: reply keyboard button %q: %v", button.Text, err)
+			}
+		}
+		markup.Keyboard = append(markup.Keyboard, row)
+	}
+	if len(markup.Keyboard) == 0 {
+		return nil, errors.New("tbot: reply keyboard is empty")
+	}
+	if utf8.RuneCountInString(markup.InputFieldPlaceholder) > 64 {
+		return nil, errors.New("tbot: input field placeholder is longer than 64 characters")
+	}
+	return &markup, nil
+}
+
+func validateReplyButton(b KeyboardButton) error {
+	if b.Text == "" {
+		return errors.New("text is empty")
+	}
+	actions := 0
+	for _, set := range []bool{
+		b.RequestUsers != nil,
+		b.RequestChat != nil,
+		b.RequestContact,
+		b.RequestLocation,
+		b.RequestPoll != nil,
+		b.WebApp != nil,
+	} {
+		if set {
+			actions++
+		}
+	}
+	if actions > 1 {
+		return fmt.Errorf("at most one action can be set, got %d", actions)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package tbot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInlineKeyboard_Build(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyboard *InlineKeyboard
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "wraps by columns",
+			keyboard: NewInlineKeyboard().Columns(2).
+				Add(InlineCallback("1", "n:1"), InlineCallback("2", "n:2"), InlineCallback("3", "n:3")).
+				Row().Row().
+				Add(InlineSwitch("Share", "")),
+			want: `{"inline_keyboard":[[{"text":"1","callback_data":"n:1"},{"text":"2","callback_data":"n:2"}],` +
+				`[{"text":"3","callback_data":"n:3"}],[{"text":"Share","switch_inline_query":""}]]}`,
+		},
+		{
+			name:     "no action",
+			keyboard: NewInlineKeyboard().Add(InlineKeyboardButton{Text: "x"}),
+			wantErr:  true,
+		},
+		{
+			name:     "long callback data",
+			keyboard: NewInlineKeyboard().Add(InlineCallback("x", strings.Repeat("d", 65))),
+			wantErr:  true,
+		},
+		{
+			name:     "pay not first",
+			keyboard: NewInlineKeyboard().Add(InlineURL("x", "https://example.com"), InlinePay("Pay")),
+			wantErr:  true,
+		},
+		{
+			name:     "too many columns",
+			keyboard: NewInlineKeyboard().Add(make9Buttons()...),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.keyboard.Build()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && structString(got) != tt.want {
+				t.Errorf("Build() = %s, want %s", structString(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestReplyKeyboard_Build(t *testing.T) {
+	got, err := NewReplyKeyboard().Resize().OneTime().
+		Add(ReplyContact("Phone"), ReplyLocation("Where")).
+		Row().
+		Add(ReplyPoll("Quiz", "quiz")).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	want := `{"keyboard":[[{"text":"Phone","request_contact":true},{"text":"Where","request_location":true}],` +
+		`[{"text":"Quiz","request_poll":{"type":"quiz"}}]],"resize_keyboard":true,"one_time_keyboard":true,"selective":false}`
+	if structString(got) != want {
+		t.Errorf("Build() = %s, want %s", structString(got), want)
+	}
+}
+
+func make9Buttons() []InlineKeyboardButton {
+	buttons := make([]InlineKeyboardButton, 9)
+	for i := range buttons {
+		buttons[i] = InlineCallback("b", "b")
+	}
+	return buttons
+}
@@ -0,0 +1,316 @@
+package tbot
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	mdFenceRegexp   = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([^\\s`]*)")
+	mdHeadingRegexp = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.*?)(\s+#+)?\s*$`)
+	mdRuleRegexp    = regexp.MustCompile(`^\s{0,3}((\*\s*){3,}|(-\s*){3,}|(_\s*){3,})$`)
+	mdQuoteRegexp   = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	mdBulletRegexp  = regexp.MustCompile(`^(\s*)[-*+]\s+(\[[ xX]\]\s+)?(.*)$`)
+	mdOrderedRegexp = regexp.MustCompile(`^(\s*)(\d{1,9}[.)])\s+(.*)$`)
+)
+
+// mdEscapable are characters which are literal when escaped with backslash
+const mdEscapable = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// ParseMarkdown converts Markdown as commonly written on GitHub to plain
+// text and entities, so it can be sent with OptEntities without MarkdownV2
+// escaping rules. Supported are **bold**, *italic* and _italic_,
+// ~~strikethrough~~, `code`, fenced code blocks with language, [links](url),
+// headings (rendered bold), block quotes, bullet, task and ordered lists, and
+// backslash escapes. Line breaks are kept as written, runs of blank lines
+// are collapsed to one.
+//
+//	text, entities := tbot.ParseMarkdown(releaseNotes)
+//	client.SendMessage(chatID, "", text, tbot.OptEntities(entities))
+func ParseMarkdown(s string) (string, []*MessageEntity) {
+	m := &markdownParser{}
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if fence := mdFenceRegexp.FindStringSubmatch(line); fence != nil {
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence[1]); i++ {
+				code = append(code, lines[i])
+			}
+			m.block(false)
+			e := m.open(&MessageEntity{Type: EntityPre, Language: fence[2]})
+			m.write(strings.Join(code, "\n"))
+			m.close(e)
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			m.blank = m.started
+			m.endQuote()
+			continue
+		}
+		if quote := mdQuoteRegexp.FindStringSubmatch(line); quote != nil {
+			m.block(true)
+			if m.quote == nil {
+				m.quote = m.open(&MessageEntity{Type: EntityBlockquote})
+			}
+			m.inline(quote[1])
+			continue
+		}
+		m.block(false)
+		switch {
+		case mdRuleRegexp.MatchString(line):
+			m.write("———")
+		case mdHeadingRegexp.MatchString(line):
+			e := m.open(&MessageEntity{Type: EntityBold})
+			m.inline(mdHeadingRegexp.FindStringSubmatch(line)[1])
+			m.close(e)
+		case mdBulletRegexp.MatchString(line):
+			item := mdBulletRegexp.FindStringSubmatch(line)
+			m.write(listIndent(item[1]))
+			switch strings.TrimSpace(item[2]) {
+			case "":
+				m.write("• ")
+			case "[ ]":
+				m.write("☐ ")
+			default:
+				m.write("☑ ")
+			}
+			m.inline(item[3])
+		case mdOrderedRegexp.MatchString(line):
+			item := mdOrderedRegexp.FindStringSubmatch(line)
+			m.write(listIndent(item[1]) + item[2] + " ")
+			m.inline(item[3])
+		default:
+			m.inline(strings.TrimSpace(line))
+		}
+	}
+	m.endQuote()
+	return m.result()
+}
+
+// listIndent keeps nesting of list items, two spaces per level
+func listIndent(indent string) string {
+	indent = strings.ReplaceAll(indent, "\t", "    ")
+	return strings.Repeat("  ", len(indent)/2)
+}
+
+type markdownParser struct {
+	markupParser
+	started bool
+	blank   bool
+	quote   *MessageEntity
+}
+
+// block starts a new line, inQuote tells whether the line belongs to a block quote
+func (m *markdownParser) block(inQuote bool) {
+	if !inQuote || m.blank {
+		m.endQuote()
+	}
+	if m.started {
+		m.write("\n")
+		if m.blank {
+			m.write("\n")
+		}
+	}
+	m.started, m.blank = true, false
+}
+
+func (m *markdownParser) endQuote() {
+	if m.quote != nil {
+		m.close(m.quote)
+		m.quote = nil
+	}
+}
+
+// inline writes s parsing inline formatting
+func (m *markdownParser) inline(s string) {
+	for i := 0; i < len(s); {
+		ch := s[i]
+		switch {
+		case ch == '\\' && i+1 < len(s) && strings.IndexByte(mdEscapable, s[i+1]) >= 0:
+			m.write(s[i+1 : i+2])
+			i += 2
+			continue
+		case ch == '`':
+			if n := m.codeSpan(s[i:]); n > 0 {
+				i += n
+				continue
+			}
+			run := len(s[i:]) - len(strings.TrimLeft(s[i:], "`"))
+			m.write(s[i : i+run])
+			i += run
+			continue
+		case ch == '[' || ch == '!' && strings.HasPrefix(s[i+1:], "["):
+			if n := m.link(s[i:]); n > 0 {
+				i += n
+				continue
+			}
+		case ch == '<':
+			if end := strings.IndexByte(s[i:], '>'); end > 0 {
+				target := s[i+1 : i+end]
+				if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") || strings.HasPrefix(target, "mailto:") {
+					// Telegram detects bare URLs itself
+					m.write(strings.TrimPrefix(target, "mailto:"))
+					i += end + 1
+					continue
+				}
+			}
+		case ch == '*' || ch == '_' || ch == '~':
+			if n := m.emphasis(s, i); n > 0 {
+				i += n
+				continue
+			}
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		m.write(s[i : i+size])
+		i += size
+	}
+}
+
+// codeSpan writes code span starting at s and returns its length, or 0 if
+// the backtick run is not closed
+func (m *markdownParser) codeSpan(s string) int {
+	run := len(s) - len(strings.TrimLeft(s, "`"))
+	fence := s[:run]
+	for j := run; j < len(s); {
+		k := strings.Index(s[j:], fence)
+		if k < 0 {
+			return 0
+		}
+		k += j
+		end := k + run
+		if end < len(s) && s[end] == '`' {
+			// longer run of backticks, not the closer
+			j = end + len(s[end:]) - len(strings.TrimLeft(s[end:], "`"))
+			continue
+		}
+		code := s[run:k]
+		if len(code) > 1 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+			code = code[1 : len(code)-1]
+		}
+		e := m.open(&MessageEntity{Type: EntityCode})
+		m.write(code)
+		m.close(e)
+		return end
+	}
+	return 0
+}
+
+// link writes [text](url) or ![alt](url) starting at s and returns its
+// length, or 0 if s does not start a link
+func (m *markdownParser) link(s string) int {
+	start := strings.IndexByte(s, '[')
+	depth, textEnd := 0, -1
+	for j := start; j < len(s) && textEnd < 0; j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				textEnd = j
+			}
+		}
+	}
+	if textEnd < 0 || !strings.HasPrefix(s[textEnd+1:], "(") {
+		return 0
+	}
+	depth, urlEnd := 0, -1
+	for j := textEnd + 1; j < len(s) && urlEnd < 0; j++ {
+		switch s[j] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				urlEnd = j
+			}
+		}
+	}
+	if urlEnd < 0 {
+		return 0
+	}
+	target := strings.TrimSpace(s[textEnd+2 : urlEnd])
+	if sp := strings.IndexAny(target, " \t"); sp >= 0 {
+		// drops link title
+		target = target[:sp]
+	}
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+
+	e := m.open(mentionOrLink(target))
+	m.inline(s[start+1 : textEnd])
+	m.close(e)
+	return urlEnd + 1
+}
+
+// emphasis writes emphasis opened at s[i] and returns its length, or 0 if it is not closed
+func (m *markdownParser) emphasis(s string, i int) int {
+	ch := s[i]
+	run := len(s[i:]) - len(strings.TrimLeft(s[i:], string(ch)))
+	var mark, entityType string
+	switch {
+	case ch == '~' && run >= 2:
+		mark, entityType = "~~", EntityStrikethrough
+	case ch == '~':
+		return 0
+	case run >= 2:
+		mark, entityType = s[i:i+2], EntityBold
+	default:
+		mark, entityType = s[i:i+1], EntityItalic
+	}
+
+	after, _ := utf8.DecodeRuneInString(s[i+len(mark):])
+	if after == utf8.RuneError || unicode.IsSpace(after) {
+		return 0
+	}
+	if ch == '_' && i > 0 {
+		// snake_case is not emphasis
+		if before, _ := utf8.DecodeLastRuneInString(s[:i]); unicode.IsLetter(before) || unicode.IsDigit(before) {
+			return 0
+		}
+	}
+
+	inner := s[i+len(mark):]
+	for j := 0; j < len(inner); j++ {
+		switch {
+		case inner[j] == '\\':
+			j++
+			continue
+		case inner[j] == '`':
+			if n := codeSpanLength(inner[j:]); n > 0 {
+				j += n - 1
+			}
+			continue
+		case !strings.HasPrefix(inner[j:], mark):
+			continue
+		}
+		if len(mark) == 1 && strings.HasPrefix(inner[j+1:], mark) {
+			// a double mark inside single emphasis
+			j++
+			continue
+		}
+		if before, _ := utf8.DecodeLastRuneInString(inner[:j]); j == 0 || unicode.IsSpace(before) {
+			continue
+		}
+		if ch == '_' {
+			if next, _ := utf8.DecodeRuneInString(inner[j+len(mark):]); unicode.IsLetter(next) || unicode.IsDigit(next) {
+				continue
+			}
+		}
+		e := m.open(&MessageEntity{Type: entityType})
+		m.inline(inner[:j])
+		m.close(e)
+		return len(mark) + j + len(mark)
+	}
+	return 0
+}
+
+// codeSpanLength returns length of code span starting at s, 0 if it is not closed
+func codeSpanLength(s string) int {
+	var probe markdownParser
+	return probe.codeSpan(s)
+}
@@ -0,0 +1,50 @@
+package tbot
+
+import (
+	"testing"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		text     string
+		entities string
+	}{
+		{
+			name:     "inline",
+			in:       "**bold _it_** and snake_case ~~old~~ `a*b` \\*x\\*",
+			text:     "bold it and snake_case old a*b *x*",
+			entities: `[{"type":"bold","offset":0,"length":7},{"type":"italic","offset":5,"length":2},{"type":"strikethrough","offset":23,"length":3},{"type":"code","offset":27,"length":3}]`,
+		},
+		{
+			name:     "link",
+			in:       "see [the **docs**](https://x.com/a_(b) \"title\") <https://y.com>",
+			text:     "see the docs https://y.com",
+			entities: `[{"type":"text_link","offset":4,"length":8,"url":"https://x.com/a_(b)"},{"type":"bold","offset":8,"length":4}]`,
+		},
+		{
+			name:     "blocks",
+			in:       "# Release 1.2\n\n\n- fix _a_\n  - [x] done\n2. two\n> quoted\n> more\n\n```go\nx := `1`\n```\ntail",
+			text:     "Release 1.2\n\n• fix a\n  ☑ done\n2. two\nquoted\nmore\n\nx := `1`\ntail",
+			entities: `[{"type":"bold","offset":0,"length":11},{"type":"italic","offset":19,"length":1},{"type":"blockquote","offset":37,"length":11},{"type":"pre","offset":50,"length":8,"language":"go"}]`,
+		},
+		{
+			name:     "unclosed",
+			in:       "2 * 3 and **open",
+			text:     "2 * 3 and **open",
+			entities: `null`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, entities := ParseMarkdown(tt.in)
+			if text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+			if got := structString(entities); got != tt.entities {
+				t.Errorf("entities = %s, want %s", got, tt.entities)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package tbot
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// markupParser collects plain text and entities while markup is parsed
+type markupParser struct {
+	text     strings.Builder
+	length   int
+	entities []*MessageEntity
+}
+
+func (p *markupParser) write(s string) {
+	p.text.WriteString(s)
+	p.length += utf16Len(s)
+}
+
+func (p *markupParser) open(e *MessageEntity) *MessageEntity {
+	e.Offset = p.length
+	p.entities = append(p.entities, e)
+	return e
+}
+
+func (p *markupParser) close(e *MessageEntity) {
+	e.Length = p.length - e.Offset
+}
+
+// result drops empty entities, they are rejected by Telegram
+func (p *markupParser) result() (string, []*MessageEntity) {
+	entities := p.entities[:0]
+	for _, e := range p.entities {
+		if e.Length > 0 {
+			entities = append(entities, e)
+		}
+	}
+	if len(entities) == 0 {
+		entities = nil
+	}
+	return p.text.String(), entities
+}
+
+func mentionOrLink(link string) *MessageEntity {
+	if strings.HasPrefix(link, "tg://user?id=") {
+		if id, err := strconv.Atoi(strings.TrimPrefix(link, "tg://user?id=")); err == nil {
+			return &MessageEntity{Type: EntityTextMention, User: &User{ID: id}}
+		}
+	}
+	return &MessageEntity{Type: EntityTextLink, URL: link}
+}
+
+var (
+	htmlTagTypes = map[string]string{
+		"b": EntityBold, "strong": EntityBold,
+		"i": EntityItalic, "em": EntityItalic,
+		"u": EntityUnderline, "ins": EntityUnderline,
+		"s": EntityStrikethrough, "strike": EntityStrikethrough, "del": EntityStrikethrough,
+		"tg-spoiler": EntitySpoiler,
+		"code":       EntityCode,
+		"pre":        EntityPre,
+		"a":          EntityTextLink,
+		"tg-emoji":   EntityCustomEmoji,
+		"blockquote": EntityBlockquote,
+		"span":       EntitySpoiler,
+	}
+	htmlAttrRegexp = regexp.MustCompile(`([a-zA-Z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+)
+
+// ParseHTML converts text formatted for OptParseModeHTML to plain text and
+// entities, it is the reverse of RenderHTML
+func ParseHTML(s string) (string, []*MessageEntity, error) {
+	type openTag struct {
+		name   string
+		entity *MessageEntity
+	}
+	var p markupParser
+	var stack []openTag
+	for len(s) > 0 {
+		lt := strings.IndexByte(s, '<')
+		if lt < 0 {
+			p.write(html.UnescapeString(s))
+			break
+		}
+		p.write(html.UnescapeString(s[:lt]))
+		gt := strings.IndexByte(s[lt:], '>')
+		if gt < 0 {
+			return "", nil, fmt.Errorf("tbot: unclosed tag at byte %d", lt)
+		}
+		tag := s[lt+1 : lt+gt]
+		s = s[lt+gt+1:]
+
+		if strings.HasPrefix(tag, "/") {
+			name := strings.ToLower(strings.TrimSpace(tag[1:]))
+			if len(stack) == 0 || stack[len(stack)-1].name != name {
+				return "", nil, fmt.Errorf("tbot: unexpected end tag </%s>", name)
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if top.entity != nil {
+				p.close(top.entity)
+			}
+			continue
+		}
+
+		name, rest, _ := strings.Cut(strings.TrimSpace(tag), " ")
+		name = strings.ToLower(name)
+		entityType, ok := htmlTagTypes[name]
+		if !ok {
+			return "", nil, fmt.Errorf("tbot: unsupported tag <%s>", name)
+		}
+		attrs := map[string]string{}
+		for _, m := range htmlAttrRegexp.FindAllStringSubmatch(rest, -1) {
+			attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3] + m[4])
+		}
+
+		var entity *MessageEntity
+		switch name {
+		case "span":
+			if attrs["class"] != "tg-spoiler" {
+				return "", nil, fmt.Errorf("tbot: unsupported tag <span>")
+			}
+			entity = &MessageEntity{Type: entityType}
+		case "a":
+			entity = mentionOrLink(attrs["href"])
+		case "tg-emoji":
+			entity = &MessageEntity{Type: entityType, CustomEmojiID: attrs["emoji-id"]}
+		case "code":
+			// <pre><code class="language-go"> sets language of the block
+			if len(stack) > 0 && stack[len(stack)-1].name == "pre" {
+				if pre := stack[len(stack)-1].entity; pre.Offset == p.length {
+					pre.Language = strings.TrimPrefix(attrs["class"], "language-")
+					stack = append(stack, openTag{name: name})
+					continue
+				}
+			}
+			entity = &MessageEntity{Type: entityType}
+		default:
+			entity = &MessageEntity{Type: entityType}
+		}
+		stack = append(stack, openTag{name: name, entity: p.open(entity)})
+	}
+	if len(stack) > 0 {
+		return "", nil, fmt.Errorf("tbot: unclosed tag <%s>", stack[len(stack)-1].name)
+	}
+	text, entities := p.result()
+	return text, entities, nil
+}
+
+var markdownV2Toggles = []struct {
+	mark       string
+	entityType string
+}{
+	// longer marks first, "__" is underline and "_" is italic
+	{"__", EntityUnderline},
+	{"||", EntitySpoiler},
+	{"_", EntityItalic},
+	{"*", EntityBold},
+	{"~", EntityStrikethrough},
+}
+
+// ParseMarkdownV2 converts text formatted for OptParseModeMarkdown to plain
+// text and entities, it is the reverse of RenderMarkdownV2
+func ParseMarkdownV2(s string) (string, []*MessageEntity, error) {
+	var p markupParser
+	open := map[string]*MessageEntity{}
+	var links []*MessageEntity
+	var quote *MessageEntity
+	lineStart := true
+
+	for i := 0; i < len(s); {
+		if lineStart {
+			lineStart = false
+			if strings.HasPrefix(s[i:], ">") || strings.HasPrefix(s[i:], "**>") {
+				if quote == nil {
+					quote = p.open(&MessageEntity{Type: EntityBlockquote})
+				}
+				i += strings.IndexByte(s[i:], '>') + 1
+				continue
+			}
+			if quote != nil {
+				// the quote ends before the line break
+				quote.Length = p.length - 1 - quote.Offset
+				quote = nil
+			}
+		}
+
+		switch ch := s[i]; {
+		case ch == '\\' && i+1 < len(s):
+			_, size := utf8.DecodeRuneInString(s[i+1:])
+			p.write(s[i+1 : i+1+size])
+			i += 1 + size
+		case ch == '\r':
+			i++
+		case ch == '\n':
+			p.write("\n")
+			i++
+			lineStart = true
+		case strings.HasPrefix(s[i:], "```"):
+			body := s[i+3:]
+			end := indexUnescaped(body, "```")
+			if end < 0 {
+				return "", nil, fmt.Errorf("tbot: unclosed pre block at byte %d", i)
+			}
+			code := body[:end]
+			var language string
+			if nl := strings.IndexByte(code, '\n'); nl >= 0 && !strings.ContainsAny(code[:nl], " \t") {
+				language, code = code[:nl], code[nl+1:]
+			}
+			code = strings.TrimSuffix(code, "\n")
+			e := p.open(&MessageEntity{Type: EntityPre, Language: language})
+			p.write(unescapeMarkdownV2(code))
+			p.close(e)
+			i += 3 + end + 3
+		case ch == '`':
+			end := indexUnescaped(s[i+1:], "`")
+			if end < 0 {
+				return "", nil, fmt.Errorf("tbot: unclosed code at byte %d", i)
+			}
+			e := p.open(&MessageEntity{Type: EntityCode})
+			p.write(unescapeMarkdownV2(s[i+1 : i+1+end]))
+			p.close(e)
+			i += 1 + end + 1
+		case ch == '[' || strings.HasPrefix(s[i:], "!["):
+			e := &MessageEntity{Type: EntityTextLink}
+			if ch == '!' {
+				e.Type = EntityCustomEmoji
+				i++
+			}
+			links = append(links, p.open(e))
+			i++
+		case ch == ']' && len(links) > 0 && strings.HasPrefix(s[i+1:], "("):
+			end := indexUnescaped(s[i+2:], ")")
+			if end < 0 {
+				return "", nil, fmt.Errorf("tbot: unclosed link at byte %d", i)
+			}
+			link := unescapeMarkdownV2(s[i+2 : i+2+end])
+			e := links[len(links)-1]
+			links = links[:len(links)-1]
+			if e.Type == EntityCustomEmoji {
+				e.CustomEmojiID = strings.TrimPrefix(link, "tg://emoji?id=")
+			} else {
+				m := mentionOrLink(link)
+				e.Type, e.URL, e.User = m.Type, m.URL, m.User
+			}
+			p.close(e)
+			i += 2 + end + 1
+		default:
+			toggled := false
+			for _, t := range markdownV2Toggles {
+				if !strings.HasPrefix(s[i:], t.mark) {
+					continue
+				}
+				if e := open[t.entityType]; e != nil {
+					p.close(e)
+					delete(open, t.entityType)
+				} else {
+					open[t.entityType] = p.open(&MessageEntity{Type: t.entityType})
+				}
+				i += len(t.mark)
+				toggled = true
+				break
+			}
+			if !toggled {
+				_, size := utf8.DecodeRuneInString(s[i:])
+				p.write(s[i : i+size])
+				i += size
+			}
+		}
+	}
+	if quote != nil {
+		p.close(quote)
+	}
+	if len(open) > 0 || len(links) > 0 {
+		return "", nil, fmt.Errorf("tbot: unclosed entity in MarkdownV2 text")
+	}
+	text, entities := p.result()
+	return text, entities, nil
+}
+
+// indexUnescaped returns index of the first sep in s not preceded by '\'
+func indexUnescaped(s, sep string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+func unescapeMarkdownV2(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,216 @@
+package tbot
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MenuNode is a node of Menu tree. A node with children (static or loaded)
+// opens a submenu when pressed, a node without them runs its Action.
+type MenuNode struct {
+	// ID identifies the node among its siblings, IDs of the path from the
+	// root are put into callback data, so keep them short and free of '/'
+	ID    string
+	Title string
+	// Text is shown under the breadcrumb while the node is open
+	Text     string
+	Children []*MenuNode
+	// Load returns children computed at the moment the node is opened,
+	// they are appended to Children
+	Load func(c *Context) ([]*MenuNode, error)
+	// Action is called when a node without children is pressed
+	Action func(c *Context) error
+	// Visible hides the node from users it returns false for
+	Visible func(c *Context) bool
+}
+
+// Menu renders a MenuNode tree as inline keyboards with back and home
+// navigation, editing the message in place. Callback data of its buttons is
+// "<name>:o:<id>/<id>/...", so name must be unique among callback routes.
+type Menu struct {
+	name     string
+	root     *MenuNode
+	columns  int
+	backText string
+	homeText string
+}
+
+// MenuOption configures Menu
+type MenuOption func(*Menu)
+
+// WithMenuColumns sets number of node buttons per row, defaults to 1
+func WithMenuColumns(n int) MenuOption {
+	return func(m *Menu) {
+		m.columns = n
+	}
+}
+
+// WithMenuLabels sets texts of back and home buttons
+func WithMenuLabels(back, home string) MenuOption {
+	return func(m *Menu) {
+		m.backText = back
+		m.homeText = home
+	}
+}
+
+// NewMenu creates Menu with root node
+func NewMenu(name string, root *MenuNode, opts ...MenuOption) *Menu {
+	m := &Menu{
+		name:     name,
+		root:     root,
+		columns:  1,
+		backText: "‹ Back",
+		homeText: "⌂ Home",
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Validate checks that callback data of every static node fits into 64
+// bytes, so a too deep tree is reported at startup instead of when the node
+// is opened. Children returned by Load are not checked.
+func (m *Menu) Validate() error {
+	return m.validate(m.root, nil)
+}
+
+func (m *Menu) validate(node *MenuNode, path []string) error {
+	for _, child := range node.Children {
+		childPath := append(path[:len(path):len(path)], child.ID)
+		if data := m.data(childPath); len(data) > MaxCallbackDataLength {
+			return fmt.Errorf("tbot: menu node %q: callback data %q exceeds 64 bytes", strings.Join(childPath, "/"), data)
+		}
+		if err := m.validate(child, childPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register adds callback route of the menu to router
+func (m *Menu) Register(r *Router) {
+	r.OnCallback(m.name+":o:*", m.open)
+}
+
+// Send sends the root of the menu to chatID
+func (m *Menu) Send(c *Context, chatID string, opts ...sendOption) (*Message, error) {
+	text, markup, err := m.Render(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, OptInlineKeyboardMarkup(markup))
+	return c.Client.SendMessage(chatID, "", text, opts...)
+}
+
+// Render returns text and keyboard of the node at path of IDs
+func (m *Menu) Render(c *Context, path []string) (string, *InlineKeyboardMarkup, error) {
+	trail, err := m.resolve(c, path)
+	if err != nil {
+		return "", nil, err
+	}
+	return m.render(c, trail)
+}
+
+func (m *Menu) render(c *Context, trail []*MenuNode) (string, *InlineKeyboardMarkup, error) {
+	node := trail[len(trail)-1]
+	children, err := m.children(c, node)
+	if err != nil {
+		return "", nil, err
+	}
+	path := make([]string, 0, len(trail)-1)
+	titles := make([]string, 0, len(trail))
+	for i, n := range trail {
+		if i > 0 {
+			path = append(path, n.ID)
+		}
+		titles = append(titles, n.Title)
+	}
+
+	kb := NewInlineKeyboard().Columns(m.columns)
+	for _, child := range children {
+		kb.Add(InlineCallback(child.Title, m.data(append(path[:len(path):len(path)], child.ID))))
+	}
+	if len(path) > 0 {
+		kb.Columns(0).Row().Add(InlineCallback(m.backText, m.data(path[:len(path)-1])))
+		if len(path) > 1 {
+			kb.Add(InlineCallback(m.homeText, m.data(nil)))
+		}
+	}
+	markup, err := kb.Build()
+	if err != nil {
+		return "", nil, err
+	}
+
+	text := strings.Join(titles, " › ")
+	if node.Text != "" {
+		text += "\n\n" + node.Text
+	}
+	return text, markup, nil
+}
+
+// resolve returns nodes from the root to the node at path. Path elements
+// which do not exist or are hidden from the user cut the path short.
+func (m *Menu) resolve(c *Context, path []string) ([]*MenuNode, error) {
+	trail := []*MenuNode{m.root}
+	for _, id := range path {
+		children, err := m.children(c, trail[len(trail)-1])
+		if err != nil {
+			return nil, err
+		}
+		var next *MenuNode
+		for _, child := range children {
+			if child.ID == id {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		trail = append(trail, next)
+	}
+	return trail, nil
+}
+
+func (m *Menu) children(c *Context, node *MenuNode) ([]*MenuNode, error) {
+	all := node.Children
+	if node.Load != nil {
+		loaded, err := node.Load(c)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all[:len(all):len(all)], loaded...)
+	}
+	visible := make([]*MenuNode, 0, len(all))
+	for _, child := range all {
+		if child.Visible == nil || child.Visible(c) {
+			visible = append(visible, child)
+		}
+	}
+	return visible, nil
+}
+
+func (m *Menu) data(path []string) string {
+	return m.name + ":o:" + strings.Join(path, "/")
+}
+
+func (m *Menu) open(c *Context) error {
+	var path []string
+	if p := c.Param("*"); p != "" {
+		path = strings.Split(p, "/")
+	}
+	trail, err := m.resolve(c, path)
+	if err != nil {
+		return err
+	}
+	node := trail[len(trail)-1]
+	if len(trail) == len(path)+1 && node.Action != nil && len(node.Children) == 0 && node.Load == nil {
+		return node.Action(c)
+	}
+	text, markup, err := m.render(c, trail)
+	if err != nil {
+		return err
+	}
+	return c.EditMessage(text, OptInlineKeyboardMarkup(markup))
+}
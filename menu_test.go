@@ -0,0 +1,156 @@
+package tbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testMenu(actions *[]string) *MenuNode {
+	action := func(name string) func(c *Context) error {
+		return func(c *Context) error {
+			*actions = append(*actions, name)
+			return nil
+		}
+	}
+	return &MenuNode{Title: "Menu", Children: []*MenuNode{
+		{ID: "settings", Title: "Settings", Text: "Choose a setting", Children: []*MenuNode{
+			{ID: "lang", Title: "Language", Children: []*MenuNode{
+				{ID: "en", Title: "English", Action: action("en")},
+				{ID: "id", Title: "Indonesia", Action: action("id")},
+			}},
+			{ID: "notify", Title: "Notifications", Action: action("notify")},
+		}},
+		{ID: "admin", Title: "Admin", Visible: func(c *Context) bool { return false }},
+		{ID: "chats", Title: "Chats", Load: func(c *Context) ([]*MenuNode, error) {
+			return []*MenuNode{{ID: "5", Title: "Group", Action: action("chat 5")}}, nil
+		}},
+	}}
+}
+
+func TestMenu_Render(t *testing.T) {
+	var actions []string
+	m := NewMenu("m", testMenu(&actions))
+	tests := []struct {
+		name string
+		path []string
+		text string
+		rows [][]string
+	}{
+		{
+			name: "root",
+			text: "Menu",
+			rows: [][]string{{"Settings", "m:o:settings"}, {"Chats", "m:o:chats"}},
+		},
+		{
+			name: "submenu",
+			path: []string{"settings"},
+			text: "Menu › Settings\n\nChoose a setting",
+			rows: [][]string{{"Language", "m:o:settings/lang"}, {"Notifications", "m:o:settings/notify"}, {"‹ Back", "m:o:"}},
+		},
+		{
+			name: "nested with home",
+			path: []string{"settings", "lang"},
+			text: "Menu › Settings › Language",
+			rows: [][]string{{"English", "m:o:settings/lang/en"}, {"Indonesia", "m:o:settings/lang/id"}, {"‹ Back", "m:o:settings", "⌂ Home", "m:o:"}},
+		},
+		{
+			name: "loaded children",
+			path: []string{"chats"},
+			text: "Menu › Chats",
+			rows: [][]string{{"Group", "m:o:chats/5"}, {"‹ Back", "m:o:"}},
+		},
+		{
+			name: "hidden node",
+			path: []string{"admin"},
+			text: "Menu",
+			rows: [][]string{{"Settings", "m:o:settings"}, {"Chats", "m:o:chats"}},
+		},
+		{
+			name: "missing node cuts the path",
+			path: []string{"settings", "missing", "en"},
+			text: "Menu › Settings\n\nChoose a setting",
+			rows: [][]string{{"Language", "m:o:settings/lang"}, {"Notifications", "m:o:settings/notify"}, {"‹ Back", "m:o:"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, markup, err := m.Render(nil, tt.path)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+			var rows [][]string
+			for _, row := range markup.InlineKeyboard {
+				var got []string
+				for _, b := range row {
+					got = append(got, b.Text, b.CallbackData)
+				}
+				rows = append(rows, got)
+			}
+			if !reflect.DeepEqual(rows, tt.rows) {
+				t.Errorf("rows = %q\nwant %q", rows, tt.rows)
+			}
+		})
+	}
+}
+
+func TestMenu_Open(t *testing.T) {
+	var edits []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.URL.Path == "/bottoken/editMessageText" {
+			edits = append(edits, r.Form.Get("text"))
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":3}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	var actions []string
+	router := NewRouter(NewClient("token", srv.URL))
+	NewMenu("m", testMenu(&actions)).Register(router)
+	for _, data := range []string{"m:o:settings/lang", "m:o:settings/lang/id", "m:o:settings", "m:o:", "m:o:chats/5", "m:o:admin"} {
+		u := &Update{CallbackQuery: &CallbackQuery{ID: "q", Data: data, Message: &Message{MessageID: 3, Chat: Chat{ID: 1}}}}
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate(%s) error = %v", data, err)
+		}
+	}
+	wantEdits := []string{"Menu › Settings › Language", "Menu › Settings\n\nChoose a setting", "Menu", "Menu"}
+	if !reflect.DeepEqual(edits, wantEdits) {
+		t.Errorf("edits = %q, want %q", edits, wantEdits)
+	}
+	if want := []string{"id", "chat 5"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("actions = %q, want %q", actions, want)
+	}
+}
+
+func TestMenu_Overflow(t *testing.T) {
+	// every level adds 11 bytes to the callback data of its children
+	root := &MenuNode{Title: "Menu"}
+	node := root
+	for i := 0; i < 6; i++ {
+		child := &MenuNode{ID: fmt.Sprintf("level%05d", i), Title: fmt.Sprint(i)}
+		node.Children = []*MenuNode{child}
+		node = child
+	}
+	m := NewMenu("m", root)
+	err := m.Validate()
+	if err == nil || !strings.Contains(err.Error(), "level00000/level00001/level00002/level00003/level00004/level00005") {
+		t.Errorf("Validate() error = %v, want the too long path", err)
+	}
+	path := []string{"level00000", "level00001", "level00002", "level00003", "level00004"}
+	if _, _, err := m.Render(nil, path); err == nil {
+		t.Error("Render() of a node with too long callback data succeeded")
+	}
+	if err := NewMenu("m", testMenu(new([]string))).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package tbot
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// ChatMigration is an upgrade of a group to a supergroup, which changes the chat id
+type ChatMigration struct {
+	FromChatID int
+	ToChatID   int
+}
+
+// ChatMigrations keeps ids of groups migrated to supergroups in storage. They
+// are recorded from failed requests of a client created WithChatMigrations and,
+// once registered in a router, from migration service messages.
+//
+//	migrations := tbot.NewChatMigrations(storage, tbot.WithMigrationHandler(func(m tbot.ChatMigration) {
+//		db.MoveSubscriptions(m.FromChatID, m.ToChatID)
+//	}))
+//	client := tbot.NewClient(token, "", tbot.WithChatMigrations(migrations))
+//	migrations.Register(router)
+type ChatMigrations struct {
+	storage Storage
+	handler func(m ChatMigration)
+}
+
+// ChatMigrationsOption configures ChatMigrations
+type ChatMigrationsOption func(*ChatMigrations)
+
+// WithMigrationHandler sets function called once for every newly recorded migration
+func WithMigrationHandler(fn func(m ChatMigration)) ChatMigrationsOption {
+	return func(m *ChatMigrations) {
+		m.handler = fn
+	}
+}
+
+// NewChatMigrations creates ChatMigrations keeping the mapping in storage
+func NewChatMigrations(storage Storage, opts ...ChatMigrationsOption) *ChatMigrations {
+	m := &ChatMigrations{storage: storage}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Register adds a middleware recording migrations from service messages to
+// router, the messages are passed on to their handlers
+func (m *ChatMigrations) Register(r *Router) {
+	r.Use(m.middleware)
+}
+
+func (m *ChatMigrations) middleware(next HandlerFunc) HandlerFunc {
+	return func(c *Context) error {
+		if msg := c.Update.Message; msg != nil {
+			var err error
+			switch {
+			case msg.MigrateToChatID != 0:
+				err = m.Record(ChatMigration{FromChatID: msg.Chat.ID, ToChatID: msg.MigrateToChatID})
+			case msg.MigrateFromChatID != 0:
+				err = m.Record(ChatMigration{FromChatID: msg.MigrateFromChatID, ToChatID: msg.Chat.ID})
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return next(c)
+	}
+}
+
+func migrationKey(chatID int) string {
+	return "tbot:migration:" + strconv.Itoa(chatID)
+}
+
+// Record stores migration and calls the handler unless it is already known
+func (m *ChatMigrations) Record(migration ChatMigration) error {
+	known, err := m.storage.Get(migrationKey(migration.FromChatID))
+	if err == nil && string(known) == strconv.Itoa(migration.ToChatID) {
+		return nil
+	}
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	if err := m.storage.Set(migrationKey(migration.FromChatID), []byte(strconv.Itoa(migration.ToChatID)), 0); err != nil {
+		return err
+	}
+	if m.handler != nil {
+		m.handler(migration)
+	}
+	return nil
+}
+
+// ChatID returns the current id of the chat, which is chatID itself if the
+// chat was not migrated
+func (m *ChatMigrations) ChatID(chatID int) (int, error) {
+	data, err := m.storage.Get(migrationKey(chatID))
+	if errors.Is(err, ErrNotFound) {
+		return chatID, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(data))
+}
+
+// withMigration sends request with the current chat id, and sends it again if
+// the chat turns out to be migrated
+func (c *Client) withMigration(request url.Values, send func(url.Values) error) error {
+	chatID, err := strconv.Atoi(request.Get("chat_id"))
+	if c.migrations == nil || err != nil {
+		// no chat or a channel username
+		return send(request)
+	}
+	if current, err := c.migrations.ChatID(chatID); err != nil {
+		c.logger.Errorf("tbot: unable to get migration of chat %d: %v", chatID, err)
+	} else if current != chatID {
+		request = withChatID(request, current)
+		chatID = current
+	}
+	err = send(request)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.MigrateToChatID == 0 {
+		return err
+	}
+	if err := c.migrations.Record(ChatMigration{FromChatID: chatID, ToChatID: apiErr.MigrateToChatID}); err != nil {
+		c.logger.Errorf("tbot: unable to record migration of chat %d: %v", chatID, err)
+	}
+	return send(withChatID(request, apiErr.MigrateToChatID))
+}
+
+// withChatID returns copy of request with chat_id replaced, so the caller's values are intact
+func withChatID(request url.Values, chatID int) url.Values {
+	req := make(url.Values, len(request))
+	for k, v := range request {
+		req[k] = v
+	}
+	req.Set("chat_id", strconv.Itoa(chatID))
+	return req
+}
@@ -0,0 +1,94 @@
+package tbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_ChatMigration(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		chatID := r.Form.Get("chat_id")
+		calls = append(calls, strings.TrimPrefix(r.URL.Path, "/bottoken/")+" "+chatID)
+		if chatID == "-5" {
+			_, _ = fmt.Fprint(w, `{"ok":false,"error_code":400,"description":"Bad Request: group chat was upgraded to a supergroup chat","parameters":{"migrate_to_chat_id":-100}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+	}))
+	defer srv.Close()
+
+	var migrated []ChatMigration
+	migrations := NewChatMigrations(NewMemoryStorage(), WithMigrationHandler(func(m ChatMigration) {
+		migrated = append(migrated, m)
+	}))
+	client := NewClient("token", srv.URL, WithChatMigrations(migrations))
+	for i := 0; i < 2; i++ {
+		if _, err := client.SendMessage("-5", "", "hello"); err != nil {
+			t.Fatalf("SendMessage() error = %v", err)
+		}
+	}
+
+	wantCalls := []string{"sendMessage -5", "sendMessage -100", "sendMessage -100"}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("calls = %v, want %v", calls, wantCalls)
+	}
+	wantMigrated := []ChatMigration{{FromChatID: -5, ToChatID: -100}}
+	if !reflect.DeepEqual(migrated, wantMigrated) {
+		t.Errorf("migrated = %v, want %v", migrated, wantMigrated)
+	}
+
+	_, err := NewClient("token", srv.URL).SendMessage("-5", "", "hello")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.MigrateToChatID != -100 || apiErr.Code != 400 {
+		t.Errorf("SendMessage() without migrations error = %#v, want APIError migrating to -100", err)
+	}
+}
+
+func TestChatMigrations_Register(t *testing.T) {
+	storage := NewMemoryStorage()
+	roster := NewRoster(storage)
+	var migrated []ChatMigration
+	migrations := NewChatMigrations(storage, WithMigrationHandler(func(m ChatMigration) {
+		migrated = append(migrated, m)
+		if err := roster.Migrate(m); err != nil {
+			t.Errorf("Migrate() error = %v", err)
+		}
+	}))
+	router := NewRouter(NewClient("token", "http://localhost"))
+	migrations.Register(router)
+	roster.Register(router)
+
+	updates := []*Update{
+		{Message: &Message{Chat: Chat{ID: -5, Type: "group", Title: "Group"}, Text: "hi"}},
+		{Message: &Message{Chat: Chat{ID: -5, Type: "group"}, MigrateToChatID: -100}},
+		{Message: &Message{Chat: Chat{ID: -100, Type: "supergroup"}, MigrateFromChatID: -5}},
+	}
+	for _, u := range updates {
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate() error = %v", err)
+		}
+	}
+
+	wantMigrated := []ChatMigration{{FromChatID: -5, ToChatID: -100}}
+	if !reflect.DeepEqual(migrated, wantMigrated) {
+		t.Errorf("migrated = %v, want %v", migrated, wantMigrated)
+	}
+	if id, err := migrations.ChatID(-5); err != nil || id != -100 {
+		t.Errorf("ChatID(-5) = %d, %v, want -100", id, err)
+	}
+	if id, err := migrations.ChatID(-7); err != nil || id != -7 {
+		t.Errorf("ChatID(-7) = %d, %v, want -7", id, err)
+	}
+	chats, err := roster.Chats()
+	if err != nil || len(chats) != 1 || chats[0].ID != -100 || chats[0].Title != "Group" {
+		t.Errorf("Chats() = %v, %v, want the migrated group", chats, err)
+	}
+}
@@ -0,0 +1,292 @@
+package tbot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DeadLetterFunc receives updates whose handler kept failing after all attempts
+type DeadLetterFunc func(u *Update, err error)
+
+// StorageDeadLetter returns DeadLetterFunc which saves failed updates as JSON
+// to storage under "<prefix><update_id>" keys.
+func StorageDeadLetter(storage Storage, prefix string, logger Logger) DeadLetterFunc {
+	return func(u *Update, handleErr error) {
+		value, err := json.Marshal(struct {
+			Update *Update `json:"update"`
+			Error  string  `json:"error"`
+			Date   int64   `json:"date"`
+		}{u, handleErr.Error(), time.Now().Unix()})
+		if err == nil {
+			err = storage.Set(prefix+strconv.Itoa(u.UpdateID), value, 0)
+		}
+		if err != nil && logger != nil {
+			logger.Errorf("tbot: unable to store dead letter %d: %v", u.UpdateID, err)
+		}
+	}
+}
+
+// OffsetTracker acknowledges handled updates and keeps the offset for
+// getUpdates at the lowest update which is not handled yet, persisting it to
+// Storage. Combined with Client.Poll and OffsetTracker.Handler it gives
+// at-least-once processing: after a restart polling resumes from the first
+// unhandled update, while updates delivered twice are recognized by update_id
+// and skipped.
+type OffsetTracker struct {
+	storage     Storage
+	key         string
+	maxAttempts int
+	backoff     time.Duration
+	deadLetter  DeadLetterFunc
+	recentSize  int
+	logger      Logger
+
+	mu        sync.Mutex
+	floor     int
+	committed int
+	inflight  map[int]bool // update id -> handled
+	recent    map[int]struct{}
+	recentIDs []int
+	changed   chan struct{}
+}
+
+// OffsetTrackerOption configures OffsetTracker
+type OffsetTrackerOption func(*OffsetTracker)
+
+// WithOffsetKey sets storage key of the offset, defaults to "tbot:offset"
+func WithOffsetKey(key string) OffsetTrackerOption {
+	return func(t *OffsetTracker) {
+		t.key = key
+	}
+}
+
+// WithMaxAttempts sets how many times a failing handler is called before the
+// update goes to the dead letter sink, defaults to 3
+func WithMaxAttempts(n int) OffsetTrackerOption {
+	return func(t *OffsetTracker) {
+		t.maxAttempts = n
+	}
+}
+
+// WithRetryBackoff sets delay before the second attempt, every next delay is
+// twice as long. Defaults to 1 second.
+func WithRetryBackoff(d time.Duration) OffsetTrackerOption {
+	return func(t *OffsetTracker) {
+		t.backoff = d
+	}
+}
+
+// WithDeadLetter sets sink for updates which could not be handled
+func WithDeadLetter(fn DeadLetterFunc) OffsetTrackerOption {
+	return func(t *OffsetTracker) {
+		t.deadLetter = fn
+	}
+}
+
+// WithDedupWindow sets how many handled update ids are remembered for
+// deduplication, defaults to 1000
+func WithDedupWindow(n int) OffsetTrackerOption {
+	return func(t *OffsetTracker) {
+		t.recentSize = n
+	}
+}
+
+// WithTrackerLogger sets logger of the tracker
+func WithTrackerLogger(logger Logger) OffsetTrackerOption {
+	return func(t *OffsetTracker) {
+		t.logger = logger
+	}
+}
+
+// NewOffsetTracker creates tracker and loads the last persisted offset from storage
+func NewOffsetTracker(storage Storage, opts ...OffsetTrackerOption) (*OffsetTracker, error) {
+	t := &OffsetTracker{
+		storage:     storage,
+		key:         "tbot:offset",
+		maxAttempts: 3,
+		backoff:     time.Second,
+		recentSize:  1000,
+		logger:      nopLogger{},
+		inflight:    map[int]bool{},
+		recent:      map[int]struct{}{},
+		changed:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.maxAttempts < 1 {
+		t.maxAttempts = 1
+	}
+	value, err := storage.Get(t.key)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("unable to load offset: %v", err)
+	default:
+		t.committed, err = strconv.Atoi(string(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset %q: %v", value, err)
+		}
+		t.floor = t.committed
+	}
+	return t, nil
+}
+
+// Offset returns the offset to request updates from: the lowest update which
+// is not acknowledged yet
+func (t *OffsetTracker) Offset() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.committed
+}
+
+// Changed returns channel which is closed the next time the offset moves
+func (t *OffsetTracker) Changed() <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.changed
+}
+
+// Begin registers update as in flight. It returns false if the update is a
+// duplicate: it is in flight already, was recently handled, or precedes the
+// persisted offset.
+func (t *OffsetTracker) Begin(id int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id < t.floor {
+		return false
+	}
+	if _, ok := t.inflight[id]; ok {
+		return false
+	}
+	if _, ok := t.recent[id]; ok {
+		return false
+	}
+	t.inflight[id] = false
+	if len(t.inflight) == 1 && id > t.committed {
+		t.committed = id
+	}
+	return true
+}
+
+// Abort forgets update registered by Begin without acknowledging it, so its
+// next delivery is not treated as a duplicate
+func (t *OffsetTracker) Abort(id int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if handled, ok := t.inflight[id]; ok && !handled {
+		delete(t.inflight, id)
+	}
+}
+
+// Done acknowledges update. The offset advances past it once all earlier
+// in-flight updates are acknowledged too.
+func (t *OffsetTracker) Done(id int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.inflight[id]; !ok {
+		return nil
+	}
+	t.inflight[id] = true
+	t.remember(id)
+
+	committed := t.committed
+	for {
+		lowest, handled := t.lowest()
+		if lowest < 0 {
+			break
+		}
+		if !handled {
+			committed = lowest
+			break
+		}
+		delete(t.inflight, lowest)
+		committed = lowest + 1
+	}
+	if committed == t.committed {
+		return nil
+	}
+	t.committed = committed
+	close(t.changed)
+	t.changed = make(chan struct{})
+	return t.storage.Set(t.key, []byte(strconv.Itoa(committed)), 0)
+}
+
+// Flush persists the current offset
+func (t *OffsetTracker) Flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.storage.Set(t.key, []byte(strconv.Itoa(t.committed)), 0)
+}
+
+// Handler wraps h with acknowledgement. A failing update is retried with
+// backoff, after the last attempt it is passed to the dead letter sink and
+// acknowledged so it does not block the offset forever. Updates abandoned
+// because ctx is done stay unacknowledged and are delivered again.
+func (t *OffsetTracker) Handler(h UpdateHandler) UpdateHandler {
+	return func(ctx context.Context, u *Update) error {
+		var err error
+		delay := t.backoff
+		for attempt := 1; attempt <= t.maxAttempts; attempt++ {
+			if err = t.call(ctx, h, u); err == nil {
+				return t.Done(u.UpdateID)
+			}
+			if ctx.Err() != nil {
+				return err
+			}
+			if attempt == t.maxAttempts {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		t.logger.Errorf("tbot: update %d failed after %d attempts: %v", u.UpdateID, t.maxAttempts, err)
+		if t.deadLetter != nil {
+			t.deadLetter(u, err)
+		}
+		if doneErr := t.Done(u.UpdateID); doneErr != nil {
+			return doneErr
+		}
+		return err
+	}
+}
+
+func (t *OffsetTracker) call(ctx context.Context, h UpdateHandler, u *Update) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(ctx, u)
+}
+
+func (t *OffsetTracker) lowest() (int, bool) {
+	lowest, handled := -1, false
+	for id, done := range t.inflight {
+		if lowest < 0 || id < lowest {
+			lowest, handled = id, done
+		}
+	}
+	return lowest, handled
+}
+
+func (t *OffsetTracker) remember(id int) {
+	if t.recentSize <= 0 {
+		return
+	}
+	t.recent[id] = struct{}{}
+	t.recentIDs = append(t.recentIDs, id)
+	if len(t.recentIDs) > t.recentSize {
+		delete(t.recent, t.recentIDs[0])
+		t.recentIDs = t.recentIDs[1:]
+	}
+}
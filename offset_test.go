@@ -0,0 +1,74 @@
+package tbot
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOffsetTracker_AdvancesPastContiguousAcks(t *testing.T) {
+	storage := NewMemoryStorage()
+	tracker, err := NewOffsetTracker(storage)
+	if err != nil {
+		t.Fatalf("NewOffsetTracker() error = %v", err)
+	}
+	for _, id := range []int{10, 11, 12} {
+		if !tracker.Begin(id) {
+			t.Fatalf("Begin(%d) = false, want true", id)
+		}
+	}
+	if tracker.Begin(11) {
+		t.Errorf("Begin(11) accepted an in-flight duplicate")
+	}
+
+	_ = tracker.Done(11)
+	if got := tracker.Offset(); got != 10 {
+		t.Errorf("Offset() = %d, want 10", got)
+	}
+	_ = tracker.Done(10)
+	if got := tracker.Offset(); got != 12 {
+		t.Errorf("Offset() = %d, want 12", got)
+	}
+	_ = tracker.Done(12)
+	if got := tracker.Offset(); got != 13 {
+		t.Errorf("Offset() = %d, want 13", got)
+	}
+
+	restored, err := NewOffsetTracker(storage)
+	if err != nil {
+		t.Fatalf("NewOffsetTracker() error = %v", err)
+	}
+	if got := restored.Offset(); got != 13 {
+		t.Errorf("restored Offset() = %d, want 13", got)
+	}
+	if restored.Begin(12) {
+		t.Errorf("restored Begin(12) accepted an update before the persisted offset")
+	}
+}
+
+func TestOffsetTracker_HandlerDeadLetters(t *testing.T) {
+	var dead []int
+	tracker, _ := NewOffsetTracker(NewMemoryStorage(),
+		WithMaxAttempts(2),
+		WithRetryBackoff(0),
+		WithDeadLetter(func(u *Update, err error) { dead = append(dead, u.UpdateID) }),
+	)
+	calls := 0
+	h := tracker.Handler(func(ctx context.Context, u *Update) error {
+		calls++
+		return errors.New("boom")
+	})
+	tracker.Begin(5)
+	if err := h(context.Background(), &Update{UpdateID: 5}); err == nil {
+		t.Errorf("handler error = nil, want error")
+	}
+	if calls != 2 {
+		t.Errorf("handler called %d times, want 2", calls)
+	}
+	if len(dead) != 1 || dead[0] != 5 {
+		t.Errorf("dead letters = %v, want [5]", dead)
+	}
+	if got := tracker.Offset(); got != 6 {
+		t.Errorf("Offset() = %d, want 6", got)
+	}
+}
@@ -0,0 +1,196 @@
+package tbot
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// PagerItem is one selectable item listed by Pager
+type PagerItem struct {
+	// ID is put into callback data, keep it short
+	ID   string
+	Text string
+}
+
+// PagerSource returns items of the page (counted from 0) and total number of items
+type PagerSource func(c *Context, page, pageSize int) ([]PagerItem, int, error)
+
+// Pager lists items as inline buttons with navigation controls below them.
+// Navigation edits the message in place. Callback data of its buttons is
+// "<name>:p:<page>" for navigation and "<name>:s:<page>:<item id>" for
+// selection, so name must be unique among callback routes.
+//
+//	pager := tbot.NewPager("inc", listIncidents,
+//		tbot.WithPagerSelect(func(c *tbot.Context, id string, page int) error {
+//			return c.EditMessage("Incident " + id)
+//		}))
+//	pager.Register(router)
+//	router.OnMessage(func(c *tbot.Context) error {
+//		_, err := pager.Send(c, strconv.Itoa(c.Update.Message.Chat.ID))
+//		return err
+//	})
+type Pager struct {
+	name     string
+	source   PagerSource
+	pageSize int
+	columns  int
+	text     func(c *Context, page, pages, total int) string
+	onSelect func(c *Context, itemID string, page int) error
+}
+
+// PagerOption configures Pager
+type PagerOption func(*Pager)
+
+// WithPageSize sets number of items per page, defaults to 5
+func WithPageSize(n int) PagerOption {
+	return func(p *Pager) {
+		p.pageSize = n
+	}
+}
+
+// WithPagerColumns sets number of item buttons per row, defaults to 1
+func WithPagerColumns(n int) PagerOption {
+	return func(p *Pager) {
+		p.columns = n
+	}
+}
+
+// WithPagerText sets function rendering message text of a page,
+// by default it is "Page <page> of <pages>"
+func WithPagerText(fn func(c *Context, page, pages, total int) string) PagerOption {
+	return func(p *Pager) {
+		p.text = fn
+	}
+}
+
+// WithPagerSelect sets handler called when an item is pressed
+func WithPagerSelect(fn func(c *Context, itemID string, page int) error) PagerOption {
+	return func(p *Pager) {
+		p.onSelect = fn
+	}
+}
+
+// NewPager creates Pager with callback data prefix name
+func NewPager(name string, source PagerSource, opts ...PagerOption) *Pager {
+	p := &Pager{
+		name:     name,
+		source:   source,
+		pageSize: 5,
+		columns:  1,
+		text: func(c *Context, page, pages, total int) string {
+			return fmt.Sprintf("Page %d of %d", page+1, pages)
+		},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.pageSize < 1 {
+		p.pageSize = 1
+	}
+	return p
+}
+
+// Register adds callback routes of the pager to router
+func (p *Pager) Register(r *Router) {
+	r.OnCallback(p.name+":p:{page}", p.navigate)
+	r.OnCallback(p.name+":s:{page}:*", p.selectItem)
+	r.OnCallback(p.name+":n", func(c *Context) error { return nil })
+}
+
+// Send sends the first page to chatID. Options are passed to Client.SendMessage.
+func (p *Pager) Send(c *Context, chatID string, opts ...sendOption) (*Message, error) {
+	text, markup, err := p.Render(c, 0)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, OptInlineKeyboardMarkup(markup))
+	return c.Client.SendMessage(chatID, "", text, opts...)
+}
+
+// Render returns text and keyboard of page
+func (p *Pager) Render(c *Context, page int) (string, *InlineKeyboardMarkup, error) {
+	if page < 0 {
+		page = 0
+	}
+	items, total, err := p.source(c, page, p.pageSize)
+	if err != nil {
+		return "", nil, err
+	}
+	pages := (total + p.pageSize - 1) / p.pageSize
+	if pages < 1 {
+		pages = 1
+	}
+
+	kb := NewInlineKeyboard().Columns(p.columns)
+	for _, item := range items {
+		kb.Add(InlineCallback(item.Text, p.name+":s:"+strconv.Itoa(page)+":"+item.ID))
+	}
+	if pages > 1 {
+		kb.Columns(0).Row()
+		kb.Add(p.controls(page, pages)...)
+	}
+	markup, err := kb.Build()
+	if err != nil {
+		return "", nil, err
+	}
+	return p.text(c, page, pages, total), markup, nil
+}
+
+// controls renders "‹ 1 2 ·3· 4 5 ›" around the current page
+func (p *Pager) controls(page, pages int) []InlineKeyboardButton {
+	const window = 5
+	first := page - window/2
+	if first > pages-window {
+		first = pages - window
+	}
+	if first < 0 {
+		first = 0
+	}
+	last := first + window
+	if last > pages {
+		last = pages
+	}
+
+	var buttons []InlineKeyboardButton
+	if page > 0 {
+		buttons = append(buttons, InlineCallback("‹", p.pageData(page-1)))
+	}
+	for i := first; i < last; i++ {
+		if i == page {
+			buttons = append(buttons, InlineCallback("·"+strconv.Itoa(i+1)+"·", p.name+":n"))
+			continue
+		}
+		buttons = append(buttons, InlineCallback(strconv.Itoa(i+1), p.pageData(i)))
+	}
+	if page < pages-1 {
+		buttons = append(buttons, InlineCallback("›", p.pageData(page+1)))
+	}
+	return buttons
+}
+
+func (p *Pager) pageData(page int) string {
+	return p.name + ":p:" + strconv.Itoa(page)
+}
+
+func (p *Pager) navigate(c *Context) error {
+	page, err := strconv.Atoi(c.Param("page"))
+	if err != nil {
+		return err
+	}
+	text, markup, err := p.Render(c, page)
+	if err != nil {
+		return err
+	}
+	return c.EditMessage(text, OptInlineKeyboardMarkup(markup))
+}
+
+func (p *Pager) selectItem(c *Context) error {
+	if p.onSelect == nil {
+		return nil
+	}
+	page, err := strconv.Atoi(c.Param("page"))
+	if err != nil {
+		return err
+	}
+	return p.onSelect(c, c.Param("*"), page)
+}
@@ -0,0 +1,252 @@
+package tbot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func testPagerSource(total int) PagerSource {
+	return func(c *Context, page, pageSize int) ([]PagerItem, int, error) {
+		var items []PagerItem
+		for i := page * pageSize; i < total && i < (page+1)*pageSize; i++ {
+			items = append(items, PagerItem{ID: strconv.Itoa(i), Text: "Item " + strconv.Itoa(i)})
+		}
+		return items, total, nil
+	}
+}
+
+func buttonTexts(row []InlineKeyboardButton) []string {
+	texts := make([]string, len(row))
+	for i, b := range row {
+		texts[i] = b.Text
+	}
+	return texts
+}
+
+func TestPager_Render(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int
+		page     int
+		text     string
+		items    int
+		controls []string
+	}{
+		{name: "single page", total: 3, page: 0, text: "Page 1 of 1", items: 3},
+		{name: "empty", total: 0, page: 0, text: "Page 1 of 1", items: 0},
+		{name: "first", total: 23, page: 0, text: "Page 1 of 5", items: 5, controls: []string{"·1·", "2", "3", "4", "5", "›"}},
+		{name: "middle", total: 23, page: 2, text: "Page 3 of 5", items: 5, controls: []string{"‹", "1", "2", "·3·", "4", "5", "›"}},
+		{name: "last", total: 23, page: 4, text: "Page 5 of 5", items: 3, controls: []string{"‹", "1", "2", "3", "4", "·5·"}},
+		{name: "window", total: 60, page: 6, text: "Page 7 of 12", items: 5, controls: []string{"‹", "5", "6", "·7·", "8", "9", "›"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewPager("inc", testPagerSource(tt.total))
+			text, markup, err := p.Render(nil, tt.page)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+			rows := markup.InlineKeyboard
+			if tt.controls != nil {
+				if got := buttonTexts(rows[len(rows)-1]); !reflect.DeepEqual(got, tt.controls) {
+					t.Errorf("controls = %q, want %q", got, tt.controls)
+				}
+				rows = rows[:len(rows)-1]
+			}
+			if len(rows) != tt.items {
+				t.Fatalf("item rows = %d, want %d", len(rows), tt.items)
+			}
+			if tt.items > 0 {
+				want := fmt.Sprintf("inc:s:%d:%d", tt.page, tt.page*5)
+				if got := rows[0][0].CallbackData; got != want {
+					t.Errorf("item callback data = %q, want %q", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPager_Callbacks(t *testing.T) {
+	var calls []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		r.Form.Set("method", r.URL.Path)
+		calls = append(calls, r.Form)
+		if r.Form.Get("inline_message_id") != "" || r.URL.Path == "/bottoken/answerCallbackQuery" {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":3}}`)
+	}))
+	defer srv.Close()
+
+	var selected []string
+	router := NewRouter(NewClient("token", srv.URL))
+	NewPager("inc", testPagerSource(23), WithPagerSelect(func(c *Context, id string, page int) error {
+		selected = append(selected, fmt.Sprintf("%s@%d", id, page))
+		return nil
+	})).Register(router)
+
+	msg := &Message{MessageID: 3, Chat: Chat{ID: -5}}
+	updates := []*Update{
+		{CallbackQuery: &CallbackQuery{ID: "1", Data: "inc:p:2", Message: msg}},
+		{CallbackQuery: &CallbackQuery{ID: "2", Data: "inc:p:1", InlineMessageID: "im"}},
+		{CallbackQuery: &CallbackQuery{ID: "3", Data: "inc:s:2:12", Message: msg}},
+		{CallbackQuery: &CallbackQuery{ID: "4", Data: "inc:n", Message: msg}},
+	}
+	for _, u := range updates {
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate(%s) error = %v", u.CallbackQuery.Data, err)
+		}
+	}
+
+	var methods []string
+	for _, call := range calls {
+		methods = append(methods, call.Get("method"))
+	}
+	wantMethods := []string{
+		"/bottoken/editMessageText", "/bottoken/answerCallbackQuery",
+		"/bottoken/editMessageText", "/bottoken/answerCallbackQuery",
+		"/bottoken/answerCallbackQuery",
+		"/bottoken/answerCallbackQuery",
+	}
+	if !reflect.DeepEqual(methods, wantMethods) {
+		t.Fatalf("methods = %q, want %q", methods, wantMethods)
+	}
+	if got := calls[0]; got.Get("chat_id") != "-5" || got.Get("message_id") != "3" || got.Get("text") != "Page 3 of 5" {
+		t.Errorf("edit of page 3 = %v", got)
+	}
+	var markup InlineKeyboardMarkup
+	if err := json.Unmarshal([]byte(calls[0].Get("reply_markup")), &markup); err != nil {
+		t.Fatalf("reply_markup = %q is not a keyboard", calls[0].Get("reply_markup"))
+	}
+	if got := markup.InlineKeyboard[0][0].CallbackData; got != "inc:s:2:10" {
+		t.Errorf("first item of page 3 = %q, want inc:s:2:10", got)
+	}
+	if got := calls[2]; got.Get("inline_message_id") != "im" || got.Get("text") != "Page 2 of 5" {
+		t.Errorf("inline edit of page 2 = %v", got)
+	}
+	if want := []string{"12@2"}; !reflect.DeepEqual(selected, want) {
+		t.Errorf("selected = %q, want %q", selected, want)
+	}
+}
+
+func TestContext_EditMessage(t *testing.T) {
+	var calls []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		r.Form.Set("method", r.URL.Path)
+		calls = append(calls, r.Form)
+		if r.Form.Get("inline_message_id") != "" {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":3}}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient("token", srv.URL)
+	markup := &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{InlineCallback("a", "b")}}}
+	tests := []struct {
+		name  string
+		query *CallbackQuery
+		want  map[string]string
+	}{
+		{
+			name:  "message",
+			query: &CallbackQuery{Message: &Message{MessageID: 3, Chat: Chat{ID: -5}}},
+			want:  map[string]string{"method": "/bottoken/editMessageText", "chat_id": "-5", "message_id": "3", "text": "text", "reply_markup": structString(markup)},
+		},
+		{
+			name:  "inline message",
+			query: &CallbackQuery{InlineMessageID: "im"},
+			want:  map[string]string{"method": "/bottoken/editMessageText", "inline_message_id": "im", "text": "text", "reply_markup": structString(markup)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			c := &Context{Context: context.Background(), Client: client, Update: &Update{CallbackQuery: tt.query}}
+			if err := c.EditMessage("text", OptInlineKeyboardMarkup(markup)); err != nil {
+				t.Fatalf("EditMessage() error = %v", err)
+			}
+			if len(calls) != 1 {
+				t.Fatalf("requests = %d, want 1", len(calls))
+			}
+			for key, want := range tt.want {
+				if got := calls[0].Get(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+
+	c := &Context{Context: context.Background(), Client: client, Update: &Update{CallbackQuery: &CallbackQuery{}}}
+	if err := c.EditMessage("text"); err == nil {
+		t.Error("EditMessage() without a message succeeded")
+	}
+	if _, err := client.EditMessageReplyMarkup("-5", 3, nil); err != nil || calls[len(calls)-1].Has("reply_markup") {
+		t.Errorf("EditMessageReplyMarkup(nil) = %v, sent %v", err, calls[len(calls)-1])
+	}
+}
+
+func TestContext_EditMarkup(t *testing.T) {
+	var calls []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		r.Form.Set("method", r.URL.Path)
+		calls = append(calls, r.Form)
+		if r.Form.Get("inline_message_id") != "" {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":3}}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient("token", srv.URL)
+	markup := &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{InlineCallback("a", "b")}}}
+	tests := []struct {
+		name  string
+		query *CallbackQuery
+		want  map[string]string
+	}{
+		{
+			name:  "message",
+			query: &CallbackQuery{Message: &Message{MessageID: 3, Chat: Chat{ID: -5}}},
+			want:  map[string]string{"method": "/bottoken/editMessageReplyMarkup", "chat_id": "-5", "message_id": "3", "reply_markup": structString(markup)},
+		},
+		{
+			name:  "inline message",
+			query: &CallbackQuery{InlineMessageID: "im"},
+			want:  map[string]string{"method": "/bottoken/editMessageReplyMarkup", "inline_message_id": "im", "reply_markup": structString(markup)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			c := &Context{Context: context.Background(), Client: client, Update: &Update{CallbackQuery: tt.query}}
+			if err := c.EditMarkup(markup); err != nil {
+				t.Fatalf("EditMarkup() error = %v", err)
+			}
+			if len(calls) != 1 {
+				t.Fatalf("requests = %d, want 1", len(calls))
+			}
+			for key, want := range tt.want {
+				if got := calls[0].Get(key); got != want {
+					t.Errorf("%s = %q, want %q", key, got, want)
+				}
+			}
+		})
+	}
+}
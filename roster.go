@@ -0,0 +1,484 @@
+package tbot
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// RosterEventType is a kind of membership change reported by Roster
+type RosterEventType int
+
+const (
+	// RosterBotAdded the bot was added to a chat
+	RosterBotAdded RosterEventType = iota
+	// RosterBotRemoved the bot was removed from a chat or blocked in a private chat
+	RosterBotRemoved
+	// RosterBotPromoted the bot became an administrator
+	RosterBotPromoted
+	// RosterBotDemoted the bot is no longer an administrator
+	RosterBotDemoted
+	// RosterMemberJoined a user joined a chat
+	RosterMemberJoined
+	// RosterMemberLeft a user left or was removed from a chat
+	RosterMemberLeft
+	// RosterMemberUpdated status or rights of a member changed
+	RosterMemberUpdated
+)
+
+// RosterEvent is a membership change. Old is nil if the previous state is unknown.
+type RosterEvent struct {
+	Type   RosterEventType
+	Chat   Chat
+	Member ChatMember
+	Old    ChatMember
+}
+
+// Roster keeps members of chats the bot is in, updated from my_chat_member,
+// chat_member, new_chat_members and left_chat_member updates. It only knows
+// users it has seen, Refresh loads administrators of a chat. chat_member
+// updates must be enabled with WithAllowedUpdates.
+//
+//	roster := tbot.NewRoster(storage, tbot.WithRosterEvents(func(c *tbot.Context, e tbot.RosterEvent) error {
+//		if e.Type == tbot.RosterBotAdded {
+//			return roster.Refresh(c.Client, e.Chat.ID)
+//		}
+//		return nil
+//	}))
+//	roster.Register(router)
+type Roster struct {
+	storage Storage
+	events  func(c *Context, e RosterEvent) error
+
+	mu sync.Mutex
+}
+
+// RosterOption configures Roster
+type RosterOption func(*Roster)
+
+// WithRosterEvents sets function called on membership changes
+func WithRosterEvents(fn func(c *Context, e RosterEvent) error) RosterOption {
+	return func(r *Roster) {
+		r.events = fn
+	}
+}
+
+// NewRoster creates Roster keeping its state in storage
+func NewRoster(storage Storage, opts ...RosterOption) *Roster {
+	r := &Roster{storage: storage}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Register adds a middleware tracking memberships to router, updates are
+// passed on to their handlers
+func (r *Roster) Register(router *Router) {
+	router.Use(r.middleware)
+}
+
+type rosterChat struct {
+	Chat    Chat                       `json:"chat"`
+	BotID   int                        `json:"bot_id,omitempty"`
+	Members map[string]json.RawMessage `json:"members"`
+}
+
+const rosterChatsKey = "tbot:roster:chats"
+
+func rosterChatKey(chatID int) string {
+	return "tbot:roster:chat:" + strconv.Itoa(chatID)
+}
+
+func (r *Roster) loadChat(chatID int) (*rosterChat, error) {
+	data, err := r.storage.Get(rosterChatKey(chatID))
+	if err != nil {
+		return nil, err
+	}
+	rc := &rosterChat{}
+	return rc, json.Unmarshal(data, rc)
+}
+
+func (r *Roster) saveChat(rc *rosterChat) error {
+	data, err := json.Marshal(rc)
+	if err != nil {
+		return err
+	}
+	return r.storage.Set(rosterChatKey(rc.Chat.ID), data, 0)
+}
+
+// chat returns stored chat, or a new one if it is not known
+func (r *Roster) chat(chat Chat) (*rosterChat, error) {
+	rc, err := r.loadChat(chat.ID)
+	if errors.Is(err, ErrNotFound) {
+		return &rosterChat{Chat: chat, Members: map[string]json.RawMessage{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rc.Chat = chat
+	return rc, nil
+}
+
+func (rc *rosterChat) member(userID int) (ChatMember, error) {
+	data, ok := rc.Members[strconv.Itoa(userID)]
+	if !ok {
+		return nil, nil
+	}
+	return unmarshalChatMember(data)
+}
+
+func (rc *rosterChat) setMember(m ChatMember) error {
+	data, err := marshalChatMember(m)
+	if err != nil {
+		return err
+	}
+	rc.Members[strconv.Itoa(m.MemberUser().ID)] = data
+	return nil
+}
+
+func (r *Roster) chatIDs() ([]int, error) {
+	data, err := r.storage.Get(rosterChatsKey)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	return ids, json.Unmarshal(data, &ids)
+}
+
+// index adds chat to or removes it from the list of the bot's chats
+func (r *Roster) index(chatID int, in bool) error {
+	ids, err := r.chatIDs()
+	if err != nil {
+		return err
+	}
+	i := sort.SearchInts(ids, chatID)
+	found := i < len(ids) && ids[i] == chatID
+	switch {
+	case in && !found:
+		ids = append(ids[:i], append([]int{chatID}, ids[i:]...)...)
+	case !in && found:
+		ids = append(ids[:i], ids[i+1:]...)
+	default:
+		return nil
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	return r.storage.Set(rosterChatsKey, data, 0)
+}
+
+func (r *Roster) middleware(next HandlerFunc) HandlerFunc {
+	return func(c *Context) error {
+		events, err := r.track(c.Update)
+		if err != nil {
+			return err
+		}
+		if r.events != nil {
+			for _, e := range events {
+				if err := r.events(c, e); err != nil {
+					return err
+				}
+			}
+		}
+		return next(c)
+	}
+}
+
+// track records membership changes of the update and returns events about them
+func (r *Roster) track(u *Update) ([]RosterEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case u.MyChatMember != nil:
+		return r.trackBot(u.MyChatMember)
+	case u.ChatMember != nil:
+		return r.trackMember(u.ChatMember)
+	case u.Message != nil && u.Message.MigrateToChatID != 0:
+		// the old group of a migration, see Migrate
+		return nil, nil
+	case u.Message != nil && u.Message.Chat.Type != "private":
+		return r.trackMessage(u.Message)
+	}
+	return nil, nil
+}
+
+func (r *Roster) trackBot(cmu *ChatMemberUpdated) ([]RosterEvent, error) {
+	old, updated := cmu.OldChatMember, cmu.NewChatMember
+	e := RosterEvent{Chat: cmu.Chat, Member: updated, Old: old}
+	_, wasAdmin := ChatMemberRights(old)
+	_, isAdmin := ChatMemberRights(updated)
+	switch {
+	case !IsChatMember(updated):
+		if err := r.storage.Delete(rosterChatKey(cmu.Chat.ID)); err != nil {
+			return nil, err
+		}
+		if !IsChatMember(old) {
+			return nil, r.index(cmu.Chat.ID, false)
+		}
+		e.Type = RosterBotRemoved
+		return []RosterEvent{e}, r.index(cmu.Chat.ID, false)
+	case !IsChatMember(old):
+		e.Type = RosterBotAdded
+	case isAdmin && !wasAdmin:
+		e.Type = RosterBotPromoted
+	case !isAdmin && wasAdmin:
+		e.Type = RosterBotDemoted
+	default:
+		e.Type = RosterMemberUpdated
+	}
+	rc, err := r.chat(cmu.Chat)
+	if err != nil {
+		return nil, err
+	}
+	rc.BotID = updated.MemberUser().ID
+	if err := rc.setMember(updated); err != nil {
+		return nil, err
+	}
+	if err := r.saveChat(rc); err != nil {
+		return nil, err
+	}
+	return []RosterEvent{e}, r.index(cmu.Chat.ID, true)
+}
+
+func (r *Roster) trackMember(cmu *ChatMemberUpdated) ([]RosterEvent, error) {
+	rc, err := r.chat(cmu.Chat)
+	if err != nil {
+		return nil, err
+	}
+	known, err := rc.member(cmu.NewChatMember.MemberUser().ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := rc.setMember(cmu.NewChatMember); err != nil {
+		return nil, err
+	}
+	if err := r.saveChat(rc); err != nil {
+		return nil, err
+	}
+	e := RosterEvent{Chat: cmu.Chat, Member: cmu.NewChatMember, Old: cmu.OldChatMember}
+	wasMember, isMember := IsChatMember(cmu.OldChatMember), IsChatMember(cmu.NewChatMember)
+	switch {
+	case isMember && !wasMember:
+		e.Type = RosterMemberJoined
+	case !isMember && wasMember:
+		e.Type = RosterMemberLeft
+	default:
+		e.Type = RosterMemberUpdated
+	}
+	if known != nil && e.Type != RosterMemberUpdated && IsChatMember(known) == isMember {
+		// already reported by a new_chat_members or left_chat_member message
+		return nil, nil
+	}
+	return []RosterEvent{e}, nil
+}
+
+func (r *Roster) trackMessage(msg *Message) ([]RosterEvent, error) {
+	rc, err := r.loadChat(msg.Chat.ID)
+	// the bot is evidently in the chat even if it was added before tracking started
+	unknown := errors.Is(err, ErrNotFound)
+	if unknown && msg.LeftChatMember != nil && msg.LeftChatMember.IsBot {
+		// most likely the bot itself, whose chat was deleted by my_chat_member
+		return nil, nil
+	}
+	if unknown {
+		rc, err = &rosterChat{Chat: msg.Chat, Members: map[string]json.RawMessage{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var events []RosterEvent
+	for i := range msg.NewChatMembers {
+		user := &msg.NewChatMembers[i]
+		if user.ID == rc.BotID {
+			// reported by my_chat_member
+			continue
+		}
+		known, err := rc.member(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if known != nil && IsChatMember(known) {
+			continue
+		}
+		m := &ChatMemberMember{User: user}
+		if err := rc.setMember(m); err != nil {
+			return nil, err
+		}
+		events = append(events, RosterEvent{Type: RosterMemberJoined, Chat: msg.Chat, Member: m, Old: known})
+	}
+	if user := msg.LeftChatMember; user != nil && user.ID != rc.BotID {
+		known, err := rc.member(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if known == nil || IsChatMember(known) {
+			m := &ChatMemberLeft{User: user}
+			if err := rc.setMember(m); err != nil {
+				return nil, err
+			}
+			events = append(events, RosterEvent{Type: RosterMemberLeft, Chat: msg.Chat, Member: m, Old: known})
+		}
+	}
+	if !unknown && len(events) == 0 {
+		return nil, nil
+	}
+	rc.Chat = msg.Chat
+	if err := r.saveChat(rc); err != nil {
+		return nil, err
+	}
+	return events, r.index(msg.Chat.ID, true)
+}
+
+// Refresh loads the chat and its administrators from Telegram
+func (r *Roster) Refresh(client *Client, chatID int) error {
+	id := strconv.Itoa(chatID)
+	chat, err := client.GetChat(id)
+	if err != nil {
+		return err
+	}
+	admins, err := client.GetChatAdministrators(id)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rc, err := r.chat(*chat)
+	if err != nil {
+		return err
+	}
+	for key, data := range rc.Members {
+		// members demoted while the bot was not receiving updates
+		m, err := unmarshalChatMember(data)
+		if err != nil {
+			return err
+		}
+		if _, ok := ChatMemberRights(m); ok && m.MemberUser().ID != rc.BotID {
+			delete(rc.Members, key)
+		}
+	}
+	for _, m := range admins {
+		if err := rc.setMember(m); err != nil {
+			return err
+		}
+	}
+	if err := r.saveChat(rc); err != nil {
+		return err
+	}
+	return r.index(chatID, true)
+}
+
+// Member returns known membership of the user in the chat or ErrNotFound
+func (r *Roster) Member(chatID, userID int) (ChatMember, error) {
+	rc, err := r.loadChat(chatID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := rc.member(userID)
+	if err == nil && m == nil {
+		err = ErrNotFound
+	}
+	return m, err
+}
+
+// IsAdmin tells whether the user is known to be the owner or an administrator of the chat
+func (r *Roster) IsAdmin(chatID, userID int) (bool, error) {
+	m, err := r.Member(chatID, userID)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, ok := ChatMemberRights(m)
+	return ok, nil
+}
+
+// Members returns known current members of the chat, ordered by user id
+func (r *Roster) Members(chatID int) ([]ChatMember, error) {
+	return r.members(chatID, IsChatMember)
+}
+
+// Admins returns known owner and administrators of the chat, ordered by user id
+func (r *Roster) Admins(chatID int) ([]ChatMember, error) {
+	return r.members(chatID, func(m ChatMember) bool {
+		_, ok := ChatMemberRights(m)
+		return ok
+	})
+}
+
+func (r *Roster) members(chatID int, filter func(ChatMember) bool) ([]ChatMember, error) {
+	rc, err := r.loadChat(chatID)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var members []ChatMember
+	for _, data := range rc.Members {
+		m, err := unmarshalChatMember(data)
+		if err != nil {
+			return nil, err
+		}
+		if filter(m) {
+			members = append(members, m)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].MemberUser().ID < members[j].MemberUser().ID
+	})
+	return members, nil
+}
+
+// Chats returns chats the bot is in, ordered by id
+func (r *Roster) Chats() ([]Chat, error) {
+	ids, err := r.chatIDs()
+	if err != nil {
+		return nil, err
+	}
+	chats := make([]Chat, 0, len(ids))
+	for _, id := range ids {
+		rc, err := r.loadChat(id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		chats = append(chats, rc.Chat)
+	}
+	return chats, nil
+}
+
+// Migrate moves the roster of a group migrated to a supergroup to the new
+// chat id, e.g. from a ChatMigrations handler
+func (r *Roster) Migrate(m ChatMigration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rc, err := r.loadChat(m.FromChatID)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	rc.Chat.ID = m.ToChatID
+	rc.Chat.Type = "supergroup"
+	if err := r.saveChat(rc); err != nil {
+		return err
+	}
+	if err := r.storage.Delete(rosterChatKey(m.FromChatID)); err != nil {
+		return err
+	}
+	if err := r.index(m.FromChatID, false); err != nil {
+		return err
+	}
+	return r.index(m.ToChatID, true)
+}
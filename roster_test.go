@@ -0,0 +1,177 @@
+package tbot
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRoster(t *testing.T) {
+	const (
+		bot   = `{"id":100,"is_bot":true,"first_name":"Bot"}`
+		alice = `{"id":1,"first_name":"Alice"}`
+		bob   = `{"id":2,"first_name":"Bob"}`
+		group = `{"id":-5,"type":"supergroup","title":"Group"}`
+	)
+	updates := []string{
+		`{"my_chat_member":{"chat":` + group + `,"from":` + alice + `,"date":1,
+			"old_chat_member":{"status":"left","user":` + bot + `},
+			"new_chat_member":{"status":"member","user":` + bot + `}}}`,
+		`{"message":{"message_id":1,"chat":` + group + `,"new_chat_members":[` + bot + `,` + bob + `]}}`,
+		`{"chat_member":{"chat":` + group + `,"from":` + bob + `,"date":2,
+			"old_chat_member":{"status":"left","user":` + bob + `},
+			"new_chat_member":{"status":"member","user":` + bob + `}}}`,
+		`{"chat_member":{"chat":` + group + `,"from":` + alice + `,"date":3,
+			"old_chat_member":{"status":"left","user":` + alice + `},
+			"new_chat_member":{"status":"creator","user":` + alice + `,"is_anonymous":false}}}`,
+		`{"my_chat_member":{"chat":` + group + `,"from":` + alice + `,"date":4,
+			"old_chat_member":{"status":"member","user":` + bot + `},
+			"new_chat_member":{"status":"administrator","user":` + bot + `,"can_delete_messages":true}}}`,
+		`{"message":{"message_id":2,"chat":{"id":-7,"type":"group","title":"Old"},"text":"hi"}}`,
+		`{"message":{"message_id":3,"chat":` + group + `,"left_chat_member":` + bob + `}}`,
+	}
+
+	router := NewRouter(NewClient("token", "http://localhost"))
+	var events []RosterEventType
+	var handled int
+	router.OnMessage(func(c *Context) error {
+		handled++
+		return nil
+	})
+	roster := NewRoster(NewMemoryStorage(), WithRosterEvents(func(c *Context, e RosterEvent) error {
+		events = append(events, e.Type)
+		return nil
+	}))
+	roster.Register(router)
+	for _, data := range updates {
+		u := &Update{}
+		if err := json.Unmarshal([]byte(data), u); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate() error = %v", err)
+		}
+	}
+
+	wantEvents := []RosterEventType{RosterBotAdded, RosterMemberJoined, RosterMemberJoined, RosterBotPromoted, RosterMemberLeft}
+	if !reflect.DeepEqual(events, wantEvents) {
+		t.Errorf("events = %v, want %v", events, wantEvents)
+	}
+	if handled != 3 {
+		t.Errorf("handled messages = %d, want 3", handled)
+	}
+
+	tests := []struct {
+		user  int
+		admin bool
+	}{
+		{1, true},
+		{2, false},
+		{100, true},
+		{3, false},
+	}
+	for _, tt := range tests {
+		admin, err := roster.IsAdmin(-5, tt.user)
+		if err != nil || admin != tt.admin {
+			t.Errorf("IsAdmin(-5, %d) = %v, %v, want %v", tt.user, admin, err, tt.admin)
+		}
+	}
+
+	admins, err := roster.Admins(-5)
+	if err != nil || len(admins) != 2 || admins[0].MemberUser().ID != 1 || admins[1].MemberUser().ID != 100 {
+		t.Errorf("Admins(-5) = %v, %v", admins, err)
+	}
+	if m, err := roster.Member(-5, 2); err != nil || m.MemberStatus() != ChatMemberStatusLeft {
+		t.Errorf("Member(-5, 2) = %v, %v, want left", m, err)
+	}
+	chats, err := roster.Chats()
+	if err != nil || len(chats) != 2 || chats[0].ID != -7 || chats[1].Title != "Group" {
+		t.Errorf("Chats() = %v, %v", chats, err)
+	}
+
+	removed := &Update{MyChatMember: &ChatMemberUpdated{
+		Chat:          Chat{ID: -5},
+		OldChatMember: &ChatMemberAdministrator{User: &User{ID: 100}},
+		NewChatMember: &ChatMemberBanned{User: &User{ID: 100}},
+	}}
+	if err := router.HandleUpdate(context.Background(), removed); err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	if events[len(events)-1] != RosterBotRemoved {
+		t.Errorf("last event = %v, want RosterBotRemoved", events[len(events)-1])
+	}
+	if chats, _ := roster.Chats(); len(chats) != 1 || chats[0].ID != -7 {
+		t.Errorf("Chats() after removal = %v", chats)
+	}
+}
+
+func TestRoster_botLeftMessage(t *testing.T) {
+	const (
+		bot   = `{"id":100,"is_bot":true,"first_name":"Bot"}`
+		alice = `{"id":1,"first_name":"Alice"}`
+		group = `{"id":-5,"type":"supergroup","title":"Group"}`
+	)
+	updates := []string{
+		`{"my_chat_member":{"chat":` + group + `,"from":` + alice + `,"date":1,
+			"old_chat_member":{"status":"left","user":` + bot + `},
+			"new_chat_member":{"status":"member","user":` + bot + `}}}`,
+		`{"my_chat_member":{"chat":` + group + `,"from":` + alice + `,"date":2,
+			"old_chat_member":{"status":"member","user":` + bot + `},
+			"new_chat_member":{"status":"kicked","user":` + bot + `,"until_date":0}}}`,
+		`{"message":{"message_id":1,"chat":` + group + `,"left_chat_member":` + bot + `}}`,
+	}
+
+	router := NewRouter(NewClient("token", "http://localhost"))
+	var events []RosterEventType
+	roster := NewRoster(NewMemoryStorage(), WithRosterEvents(func(c *Context, e RosterEvent) error {
+		events = append(events, e.Type)
+		return nil
+	}))
+	roster.Register(router)
+	for _, data := range updates {
+		u := &Update{}
+		if err := json.Unmarshal([]byte(data), u); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate() error = %v", err)
+		}
+	}
+
+	if want := []RosterEventType{RosterBotAdded, RosterBotRemoved}; !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+	if chats, err := roster.Chats(); err != nil || len(chats) != 0 {
+		t.Errorf("Chats() = %v, %v, want none", chats, err)
+	}
+}
+
+func TestChatMemberUpdated_JSON(t *testing.T) {
+	in := &ChatMemberUpdated{
+		Chat:          Chat{ID: -5},
+		From:          &User{ID: 1},
+		OldChatMember: &ChatMemberMember{User: &User{ID: 2}},
+		NewChatMember: &ChatMemberRestricted{User: &User{ID: 2}, IsMember: true},
+	}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	out := &ChatMemberUpdated{}
+	if err := json.Unmarshal(data, out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+
+	data, err = json.Marshal(ChatMemberUpdated{})
+	if err != nil {
+		t.Fatalf("json.Marshal() of zero value error = %v", err)
+	}
+	out = &ChatMemberUpdated{}
+	if err := json.Unmarshal(data, out); err != nil || out.OldChatMember != nil || out.NewChatMember != nil {
+		t.Errorf("round trip of zero value %s = %+v, %v", data, out, err)
+	}
+}
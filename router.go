@@ -0,0 +1,365 @@
+package tbot
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HandlerFunc handles an update routed by Router
+type HandlerFunc func(c *Context) error
+
+// Middleware wraps handlers of Router. It runs for every update, next is a
+// no-op for updates no route matched.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Context carries the update being handled by Router together with the
+// client and parameters extracted by the matched route
+type Context struct {
+	context.Context
+	Client *Client
+	Update *Update
+
+	params    map[string]string
+	answered  bool
+	bundle    *Bundle
+	localizer *Localizer
+}
+
+// Param returns route parameter by name, or empty string if there is none
+func (c *Context) Param(name string) string {
+	return c.params[name]
+}
+
+// Localizer returns Localizer of the language of the user who sent the
+// update. If the router has no Bundle (see WithBundle) the localizer returns
+// keys as they are.
+func (c *Context) Localizer() *Localizer {
+	if c.localizer == nil && c.bundle == nil {
+		c.localizer = &Localizer{}
+		if u := c.Update.From(); u != nil {
+			c.localizer.lang = u.LanguageCode
+		}
+	}
+	if c.localizer == nil {
+		c.localizer = c.bundle.ForUser(c.Update.From())
+	}
+	return c.localizer
+}
+
+// T translates message key to the language of the user, see Localizer.T
+func (c *Context) T(key string, args ...any) string {
+	return c.Localizer().T(key, args...)
+}
+
+// TN translates plural message key to the language of the user, see Localizer.TN
+func (c *Context) TN(key string, n int, args ...any) string {
+	return c.Localizer().TN(key, n, args...)
+}
+
+// SetLanguage overrides language of the user who sent the update
+func (c *Context) SetLanguage(lang string) error {
+	u := c.Update.From()
+	if u == nil {
+		return errors.New("tbot: update has no user")
+	}
+	if c.bundle == nil {
+		return errors.New("tbot: router has no localization bundle")
+	}
+	c.localizer = nil
+	return c.bundle.SetUserLanguage(u.ID, lang)
+}
+
+// CallbackQuery returns callback query of the update, or nil
+func (c *Context) CallbackQuery() *CallbackQuery {
+	return c.Update.CallbackQuery
+}
+
+// AnswerCallback answers the callback query of the update. Router does not
+// answer the query again once it was answered here.
+// Available options are the same as for Client.AnswerCallbackQuery.
+func (c *Context) AnswerCallback(opts ...sendOption) error {
+	cq := c.Update.CallbackQuery
+	if cq == nil || c.answered {
+		return nil
+	}
+	c.answered = true
+	return c.Client.AnswerCallbackQuery(cq.ID, opts...)
+}
+
+// EditMessage edits text of the message whose inline keyboard sent the
+// callback query of the update. Available options are the same as for
+// Client.EditMessageText.
+func (c *Context) EditMessage(text string, opts ...sendOption) error {
+	cq := c.Update.CallbackQuery
+	switch {
+	case cq == nil:
+		return errors.New("tbot: update has no callback query")
+	case cq.Message != nil:
+		_, err := c.Client.EditMessageText(strconv.Itoa(cq.Message.Chat.ID), cq.Message.MessageID, text, opts...)
+		return err
+	case cq.InlineMessageID != "":
+		return c.Client.EditInlineMessageText(cq.InlineMessageID, text, opts...)
+	}
+	return errors.New("tbot: callback query has no message to edit")
+}
+
+// EditMarkup replaces inline keyboard of the message whose keyboard sent the
+// callback query of the update
+func (c *Context) EditMarkup(markup *InlineKeyboardMarkup) error {
+	cq := c.Update.CallbackQuery
+	switch {
+	case cq == nil:
+		return errors.New("tbot: update has no callback query")
+	case cq.Message != nil:
+		_, err := c.Client.EditMessageReplyMarkup(strconv.Itoa(cq.Message.Chat.ID), cq.Message.MessageID, markup)
+		return err
+	case cq.InlineMessageID != "":
+		return c.Client.EditInlineMessageReplyMarkup(cq.InlineMessageID, markup)
+	}
+	return errors.New("tbot: callback query has no message to edit")
+}
+
+// InlineQuery returns inline query of the update, or nil
+func (c *Context) InlineQuery() *InlineQuery {
+	return c.Update.InlineQuery
+}
+
+// AnswerInline answers the inline query of the update.
+// Available options are the same as for Client.AnswerInlineQuery.
+func (c *Context) AnswerInline(results []InlineQueryResult, opts ...sendOption) error {
+	iq := c.Update.InlineQuery
+	if iq == nil {
+		return nil
+	}
+	return c.Client.AnswerInlineQuery(iq.ID, results, opts...)
+}
+
+// JoinRequest returns chat join request of the update, or nil
+func (c *Context) JoinRequest() *ChatJoinRequest {
+	return c.Update.ChatJoinRequest
+}
+
+// ApproveJoin approves the chat join request of the update
+func (c *Context) ApproveJoin() error {
+	jr := c.Update.ChatJoinRequest
+	if jr == nil {
+		return errors.New("tbot: update has no chat join request")
+	}
+	return c.Client.ApproveChatJoinRequest(strconv.Itoa(jr.Chat.ID), jr.From.ID)
+}
+
+// DeclineJoin declines the chat join request of the update
+func (c *Context) DeclineJoin() error {
+	jr := c.Update.ChatJoinRequest
+	if jr == nil {
+		return errors.New("tbot: update has no chat join request")
+	}
+	return c.Client.DeclineChatJoinRequest(strconv.Itoa(jr.Chat.ID), jr.From.ID)
+}
+
+type callbackRoute struct {
+	re      *regexp.Regexp
+	handler HandlerFunc
+}
+
+// Router dispatches updates to handlers. Its HandleUpdate method is an
+// UpdateHandler, so it can be used directly with Bot or WorkerPool.
+type Router struct {
+	client       *Client
+	callbacks    []callbackRoute
+	message      HandlerFunc
+	inlineQuery  HandlerFunc
+	chosenInline HandlerFunc
+	joinRequest  HandlerFunc
+	myMember     HandlerFunc
+	member       HandlerFunc
+	fallback     HandlerFunc
+	autoAnswer   bool
+	bundle       *Bundle
+	middlewares  []Middleware
+}
+
+// RouterOption configures Router
+type RouterOption func(*Router)
+
+// WithoutAutoAnswer disables answering callback queries left unanswered by handlers
+func WithoutAutoAnswer() RouterOption {
+	return func(r *Router) {
+		r.autoAnswer = false
+	}
+}
+
+// WithBundle makes messages of bundle available to handlers with Context.T
+func WithBundle(b *Bundle) RouterOption {
+	return func(r *Router) {
+		r.bundle = b
+	}
+}
+
+// NewRouter creates Router. By default every callback query which was not
+// answered by its handler (or matched no route) is answered with an empty
+// answer, so the user's client stops showing the loading indicator.
+func NewRouter(client *Client, opts ...RouterOption) *Router {
+	r := &Router{
+		client:     client,
+		autoAnswer: true,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// OnCallback registers handler for callback queries whose data matches pattern.
+// The pattern is matched against the whole data. "{name}" matches a part of
+// data up to the next ':' and is available as c.Param("name"), a trailing "*"
+// matches the rest of data and is available as c.Param("*"). For example
+// "page:{list}:{n}" matches "page:users:2", and "menu:*" matches any data
+// starting with "menu:". Routes are tried in the order they were registered.
+func (r *Router) OnCallback(pattern string, handler HandlerFunc) {
+	r.OnCallbackRegexp(compileCallbackPattern(pattern), handler)
+}
+
+// OnCallbackRegexp registers handler for callback queries whose data matches
+// re. Named groups are available as route parameters.
+func (r *Router) OnCallbackRegexp(re *regexp.Regexp, handler HandlerFunc) {
+	r.callbacks = append(r.callbacks, callbackRoute{re: re, handler: handler})
+}
+
+// OnMessage sets handler for new messages
+func (r *Router) OnMessage(handler HandlerFunc) {
+	r.message = handler
+}
+
+// OnInlineQuery sets handler for inline queries
+func (r *Router) OnInlineQuery(handler HandlerFunc) {
+	r.inlineQuery = handler
+}
+
+// OnChosenInlineResult sets handler for chosen inline results
+func (r *Router) OnChosenInlineResult(handler HandlerFunc) {
+	r.chosenInline = handler
+}
+
+// OnJoinRequest sets handler for requests to join chats administered by the bot
+func (r *Router) OnJoinRequest(handler HandlerFunc) {
+	r.joinRequest = handler
+}
+
+// OnMyChatMember sets handler for changes of the bot's own membership in chats
+func (r *Router) OnMyChatMember(handler HandlerFunc) {
+	r.myMember = handler
+}
+
+// OnChatMember sets handler for changes of membership of other users, they
+// are only received if enabled with WithAllowedUpdates
+func (r *Router) OnChatMember(handler HandlerFunc) {
+	r.member = handler
+}
+
+// Use adds middlewares, the first added is the outermost
+func (r *Router) Use(mw ...Middleware) {
+	r.middlewares = append(r.middlewares, mw...)
+}
+
+// OnUpdate sets handler for updates no other handler matched
+func (r *Router) OnUpdate(handler HandlerFunc) {
+	r.fallback = handler
+}
+
+// HandleUpdate routes update to the matching handler
+func (r *Router) HandleUpdate(ctx context.Context, u *Update) (err error) {
+	c := &Context{Context: ctx, Client: r.client, Update: u, bundle: r.bundle}
+	if u.CallbackQuery != nil && r.autoAnswer {
+		// deferred so the query is answered even if the handler panics
+		defer func() {
+			if answerErr := c.AnswerCallback(); err == nil {
+				err = answerErr
+			}
+		}()
+	}
+	handler := r.route(c)
+	if handler == nil {
+		if len(r.middlewares) == 0 {
+			return nil
+		}
+		handler = func(c *Context) error { return nil }
+	}
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+	return handler(c)
+}
+
+func (r *Router) route(c *Context) HandlerFunc {
+	u := c.Update
+	switch {
+	case u.CallbackQuery != nil:
+		for _, route := range r.callbacks {
+			match := route.re.FindStringSubmatch(u.CallbackQuery.Data)
+			if match == nil {
+				continue
+			}
+			c.params = map[string]string{}
+			for i, name := range route.re.SubexpNames() {
+				if name != "" {
+					c.params[name] = match[i]
+				}
+			}
+			if rest := route.re.SubexpIndex("rest__"); rest >= 0 {
+				delete(c.params, "rest__")
+				c.params["*"] = match[rest]
+			}
+			return route.handler
+		}
+	case u.Message != nil:
+		if r.message != nil {
+			return r.message
+		}
+	case u.InlineQuery != nil:
+		if r.inlineQuery != nil {
+			return r.inlineQuery
+		}
+	case u.ChosenInlineResult != nil:
+		if r.chosenInline != nil {
+			return r.chosenInline
+		}
+	case u.ChatJoinRequest != nil:
+		if r.joinRequest != nil {
+			return r.joinRequest
+		}
+	case u.MyChatMember != nil:
+		if r.myMember != nil {
+			return r.myMember
+		}
+	case u.ChatMember != nil:
+		if r.member != nil {
+			return r.member
+		}
+	}
+	return r.fallback
+}
+
+var callbackParam = regexp.MustCompile(`\{(\w+)\}`)
+
+func compileCallbackPattern(pattern string) *regexp.Regexp {
+	rest := strings.HasSuffix(pattern, "*")
+	pattern = strings.TrimSuffix(pattern, "*")
+	var b strings.Builder
+	b.WriteString("^")
+	last := 0
+	for _, loc := range callbackParam.FindAllStringSubmatchIndex(pattern, -1) {
+		b.WriteString(regexp.QuoteMeta(pattern[last:loc[0]]))
+		b.WriteString("(?P<" + pattern[loc[2]:loc[3]] + ">[^:]*)")
+		last = loc[1]
+	}
+	b.WriteString(regexp.QuoteMeta(pattern[last:]))
+	if rest {
+		b.WriteString("(?P<rest__>.*)")
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
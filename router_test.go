@@ -0,0 +1,83 @@
+package tbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestRouter_OnCallback(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		data    string
+		want    map[string]string
+	}{
+		{name: "literal", pattern: "ping", data: "ping", want: map[string]string{}},
+		{name: "literal mismatch", pattern: "ping", data: "pingpong", want: nil},
+		{name: "params", pattern: "page:{list}:{n}", data: "page:users:2", want: map[string]string{"list": "users", "n": "2"}},
+		{name: "param stops at separator", pattern: "del:{id}", data: "del:1:2", want: nil},
+		{name: "prefix", pattern: "menu:*", data: "menu:a:b", want: map[string]string{"*": "a:b"}},
+		{name: "meta characters", pattern: "a.b:{x}", data: "a.b:1", want: map[string]string{"x": "1"}},
+	}
+	var answered []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		answered = append(answered, r.Form.Get("callback_query_id"))
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			answered = nil
+			router := NewRouter(NewClient("token", srv.URL))
+			var got map[string]string
+			router.OnCallback(tt.pattern, func(c *Context) error {
+				got = c.params
+				return nil
+			})
+			u := &Update{CallbackQuery: &CallbackQuery{ID: "q", Data: tt.data}}
+			if err := router.HandleUpdate(context.Background(), u); err != nil {
+				t.Fatalf("HandleUpdate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("params = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(answered, []string{"q"}) {
+				t.Errorf("answered = %v, want [q]", answered)
+			}
+		})
+	}
+}
+
+func TestRouter_OnJoinRequest(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		calls = append(calls, r.URL.Path+" "+r.Form.Get("chat_id")+" "+r.Form.Get("user_id"))
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	router := NewRouter(NewClient("token", srv.URL))
+	router.OnJoinRequest(func(c *Context) error {
+		if c.JoinRequest().Bio == "spam" {
+			return c.DeclineJoin()
+		}
+		return c.ApproveJoin()
+	})
+	for _, bio := range []string{"hello", "spam"} {
+		u := &Update{ChatJoinRequest: &ChatJoinRequest{Chat: Chat{ID: -5}, From: &User{ID: 7}, Bio: bio}}
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate() error = %v", err)
+		}
+	}
+	want := []string{"/bottoken/approveChatJoinRequest -5 7", "/bottoken/declineChatJoinRequest -5 7"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
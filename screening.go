@@ -0,0 +1,401 @@
+package tbot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ScreeningQuestion is asked to users requesting to join a chat
+type ScreeningQuestion struct {
+	// ID is the key of the answer in ScreeningApplication.Answers
+	ID   string
+	Text string
+	// Options are offered as buttons, without options the answer is a text message
+	Options []string
+}
+
+// ScreeningDecision is the outcome of a screening
+type ScreeningDecision int
+
+const (
+	// ScreeningApprove approves the join request
+	ScreeningApprove ScreeningDecision = iota
+	// ScreeningDecline declines the join request
+	ScreeningDecline
+	// ScreeningReview forwards the answers to the admin chat with Approve and Decline buttons
+	ScreeningReview
+)
+
+// ScreeningApplication is the state of one user's screening
+type ScreeningApplication struct {
+	ChatID    int               `json:"chat_id"`
+	ChatTitle string            `json:"chat_title"`
+	User      *User             `json:"user"`
+	Bio       string            `json:"bio,omitempty"`
+	Step      int               `json:"step"`
+	Answers   map[string]string `json:"answers"`
+}
+
+// ScreeningTexts are texts sent by Screening. "%s" in Intro is replaced by
+// the chat title, other texts are sent as they are.
+type ScreeningTexts struct {
+	Intro    string
+	Approved string
+	Declined string
+	Review   string
+	Approve  string
+	Decline  string
+}
+
+// Screening asks users who request to join a chat a series of questions in
+// a private chat, then approves or declines the request, or forwards the
+// answers to an admin chat to decide. The state of the conversation is kept
+// in storage, so the screening survives restarts. A user has one screening
+// at a time, a new join request restarts it. Callback data of its buttons
+// starts with "<name>:", so name must be unique among callback routes.
+//
+//	screening := tbot.NewScreening("join", storage, []tbot.ScreeningQuestion{
+//		{ID: "rules", Text: "Do you accept the rules?", Options: []string{"Yes", "No"}},
+//		{ID: "about", Text: "Tell us about yourself"},
+//	}, tbot.WithAdminReview(adminChatID))
+//	screening.Register(router)
+type Screening struct {
+	name        string
+	storage     Storage
+	questions   []ScreeningQuestion
+	decide      func(c *Context, app *ScreeningApplication) (ScreeningDecision, error)
+	adminChatID string
+	ttl         time.Duration
+	texts       ScreeningTexts
+}
+
+// ScreeningOption configures Screening
+type ScreeningOption func(*Screening)
+
+// WithScreeningDecision sets function deciding on answered applications.
+// By default applications are approved, or reviewed if an admin chat is set.
+func WithScreeningDecision(fn func(c *Context, app *ScreeningApplication) (ScreeningDecision, error)) ScreeningOption {
+	return func(s *Screening) {
+		s.decide = fn
+	}
+}
+
+// WithAdminReview sets chat where applications decided with ScreeningReview
+// are sent, chatID must be numeric. Only administrators of the chat can decide.
+func WithAdminReview(chatID string) ScreeningOption {
+	return func(s *Screening) {
+		s.adminChatID = chatID
+	}
+}
+
+// WithScreeningTTL sets how long unfinished screenings are kept, defaults to 48 hours
+func WithScreeningTTL(ttl time.Duration) ScreeningOption {
+	return func(s *Screening) {
+		s.ttl = ttl
+	}
+}
+
+// WithScreeningTexts replaces texts sent by Screening
+func WithScreeningTexts(texts ScreeningTexts) ScreeningOption {
+	return func(s *Screening) {
+		s.texts = texts
+	}
+}
+
+// NewScreening creates Screening asking questions in order
+func NewScreening(name string, storage Storage, questions []ScreeningQuestion, opts ...ScreeningOption) *Screening {
+	s := &Screening{
+		name:      name,
+		storage:   storage,
+		questions: questions,
+		ttl:       48 * time.Hour,
+		texts: ScreeningTexts{
+			Intro:    "You asked to join %s. Please answer a few questions first.",
+			Approved: "Your request was approved, welcome!",
+			Declined: "Sorry, your request was declined.",
+			Review:   "Thank you! Your answers were sent to the admins.",
+			Approve:  "Approve",
+			Decline:  "Decline",
+		},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.decide == nil {
+		s.decide = func(c *Context, app *ScreeningApplication) (ScreeningDecision, error) {
+			if s.adminChatID != "" {
+				return ScreeningReview, nil
+			}
+			return ScreeningApprove, nil
+		}
+	}
+	return s
+}
+
+// Register adds join request, callback and answer handling to router. Text
+// answers are taken by a middleware, other private messages pass through.
+// Register sets the join request handler of router, replacing the one set
+// before. To screen only some chats, or to use several Screenings with one
+// router, set own handler with OnJoinRequest after Register and call
+// HandleJoinRequest of the right Screening from it.
+func (s *Screening) Register(r *Router) {
+	r.OnJoinRequest(s.HandleJoinRequest)
+	r.OnCallback(s.name+":a:{step}:{option}", s.answerOption)
+	if s.adminChatID != "" {
+		r.OnCallback(s.name+":r:{chat}:{user}:{verdict}", s.review)
+	}
+	r.Use(s.middleware)
+}
+
+func (s *Screening) key(userID int) string {
+	return "tbot:screening:" + s.name + ":" + strconv.Itoa(userID)
+}
+
+// load returns the stored application of the user. Its step may be past the
+// last question if questions were removed since the application was saved,
+// such an application is finished with the answers given so far.
+func (s *Screening) load(userID int) (*ScreeningApplication, error) {
+	data, err := s.storage.Get(s.key(userID))
+	if err != nil {
+		return nil, err
+	}
+	app := &ScreeningApplication{}
+	return app, json.Unmarshal(data, app)
+}
+
+func (s *Screening) save(app *ScreeningApplication) error {
+	data, err := json.Marshal(app)
+	if err != nil {
+		return err
+	}
+	return s.storage.Set(s.key(app.User.ID), data, s.ttl)
+}
+
+// HandleJoinRequest starts screening of the user who sent the join request
+// of the update, restarting one in progress
+func (s *Screening) HandleJoinRequest(c *Context) error {
+	jr := c.JoinRequest()
+	app := &ScreeningApplication{
+		ChatID:    jr.Chat.ID,
+		ChatTitle: jr.Chat.Title,
+		User:      jr.From,
+		Bio:       jr.Bio,
+		Answers:   map[string]string{},
+	}
+	if err := s.save(app); err != nil {
+		return err
+	}
+	if len(s.questions) == 0 {
+		return s.finish(c, app)
+	}
+	if _, err := c.Client.SendMessage(strconv.Itoa(app.User.ID), "", strings.Replace(s.texts.Intro, "%s", app.ChatTitle, 1)); err != nil {
+		return err
+	}
+	return s.ask(c, app)
+}
+
+func (s *Screening) ask(c *Context, app *ScreeningApplication) error {
+	q := s.questions[app.Step]
+	var opts []sendOption
+	if len(q.Options) > 0 {
+		kb := NewInlineKeyboard().Columns(2)
+		for i, option := range q.Options {
+			kb.Add(InlineCallback(option, fmt.Sprintf("%s:a:%d:%d", s.name, app.Step, i)))
+		}
+		markup, err := kb.Build()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, OptInlineKeyboardMarkup(markup))
+	}
+	_, err := c.Client.SendMessage(strconv.Itoa(app.User.ID), "", q.Text, opts...)
+	return err
+}
+
+// answer records answer to the current question and moves to the next one
+func (s *Screening) answer(c *Context, app *ScreeningApplication, answer string) error {
+	app.Answers[s.questions[app.Step].ID] = answer
+	app.Step++
+	if err := s.save(app); err != nil {
+		return err
+	}
+	if app.Step == len(s.questions) {
+		return s.finish(c, app)
+	}
+	return s.ask(c, app)
+}
+
+func (s *Screening) answerOption(c *Context) error {
+	app, err := s.load(c.CallbackQuery().From.ID)
+	if errors.Is(err, ErrNotFound) {
+		return c.AnswerCallback()
+	}
+	if err != nil {
+		return err
+	}
+	if app.Step >= len(s.questions) {
+		return s.finish(c, app)
+	}
+	step, err := strconv.Atoi(c.Param("step"))
+	if err != nil {
+		return err
+	}
+	option, err := strconv.Atoi(c.Param("option"))
+	if err != nil {
+		return err
+	}
+	if step != app.Step || option < 0 || option >= len(s.questions[step].Options) {
+		// a button of an already answered question
+		return c.AnswerCallback()
+	}
+	chosen := s.questions[step].Options[option]
+	if err := c.EditMessage(s.questions[step].Text + "\n\n✓ " + chosen); err != nil {
+		return err
+	}
+	return s.answer(c, app, chosen)
+}
+
+func (s *Screening) middleware(next HandlerFunc) HandlerFunc {
+	return func(c *Context) error {
+		msg := c.Update.Message
+		if msg == nil || msg.Chat.Type != "private" || msg.From == nil || msg.Text == "" {
+			return next(c)
+		}
+		app, err := s.load(msg.From.ID)
+		if errors.Is(err, ErrNotFound) {
+			return next(c)
+		}
+		if err != nil {
+			return err
+		}
+		if app.Step >= len(s.questions) {
+			return s.finish(c, app)
+		}
+		if len(s.questions[app.Step].Options) > 0 {
+			// the question is answered with buttons
+			return next(c)
+		}
+		return s.answer(c, app, msg.Text)
+	}
+}
+
+// finish carries out the decision on a fully answered application. The
+// application is deleted only after that, so if a request fails the next
+// message or button press of the user finishes it again.
+func (s *Screening) finish(c *Context, app *ScreeningApplication) error {
+	decision, err := s.decide(c, app)
+	if err != nil {
+		return err
+	}
+	chatID := strconv.Itoa(app.ChatID)
+	var notice string
+	switch decision {
+	case ScreeningApprove:
+		if err := c.Client.ApproveChatJoinRequest(chatID, app.User.ID); err != nil {
+			return err
+		}
+		notice = s.texts.Approved
+	case ScreeningDecline:
+		if err := c.Client.DeclineChatJoinRequest(chatID, app.User.ID); err != nil {
+			return err
+		}
+		notice = s.texts.Declined
+	case ScreeningReview:
+		if s.adminChatID == "" {
+			return errors.New("tbot: screening review requires an admin chat")
+		}
+		if err := s.sendReview(c, app); err != nil {
+			return err
+		}
+		notice = s.texts.Review
+	default:
+		return fmt.Errorf("tbot: unknown screening decision %d", decision)
+	}
+	if err := s.storage.Delete(s.key(app.User.ID)); err != nil {
+		return err
+	}
+	_, err = c.Client.SendMessage(strconv.Itoa(app.User.ID), "", notice)
+	return err
+}
+
+func (s *Screening) sendReview(c *Context, app *ScreeningApplication) error {
+	b := NewTextBuilder().
+		Text("Join request to ").Bold(app.ChatTitle).Text(" from ").
+		Mention(strings.TrimSpace(app.User.FirstName+" "+app.User.LastName), app.User)
+	if app.User.Username != "" {
+		b.Text(" @" + app.User.Username)
+	}
+	if app.Bio != "" {
+		b.Text("\n").Italic(app.Bio)
+	}
+	for _, q := range s.questions {
+		b.Text("\n\n").Bold(q.Text).Text("\n" + app.Answers[q.ID])
+	}
+	data := fmt.Sprintf("%s:r:%d:%d:", s.name, app.ChatID, app.User.ID)
+	markup, err := NewInlineKeyboard().Add(
+		InlineCallback(s.texts.Approve, data+"y"),
+		InlineCallback(s.texts.Decline, data+"n"),
+	).Build()
+	if err != nil {
+		return err
+	}
+	_, err = c.Client.SendMessage(s.adminChatID, "", b.String(), OptEntities(b.Entities()), OptInlineKeyboardMarkup(markup))
+	return err
+}
+
+// reviewer tells whether the callback was pressed by an administrator in the
+// admin chat, callback data can be forged by a malicious client
+func (s *Screening) reviewer(c *Context) (bool, error) {
+	q := c.CallbackQuery()
+	if q.Message == nil || strconv.Itoa(q.Message.Chat.ID) != s.adminChatID || q.From == nil {
+		return false, nil
+	}
+	m, err := c.Client.GetChatMember(s.adminChatID, q.From.ID)
+	if err != nil {
+		return false, err
+	}
+	_, ok := ChatMemberRights(m)
+	return ok, nil
+}
+
+func (s *Screening) review(c *Context) error {
+	if ok, err := s.reviewer(c); err != nil || !ok {
+		if err != nil {
+			return err
+		}
+		return c.AnswerCallback()
+	}
+	userID, err := strconv.Atoi(c.Param("user"))
+	if err != nil {
+		return err
+	}
+	approve := c.Param("verdict") == "y"
+	if approve {
+		err = c.Client.ApproveChatJoinRequest(c.Param("chat"), userID)
+	} else {
+		err = c.Client.DeclineChatJoinRequest(c.Param("chat"), userID)
+	}
+	if err != nil {
+		// the request may have been processed already, e.g. by another admin
+		return c.AnswerCallback(OptCallbackText(err.Error()), OptShowAlert)
+	}
+
+	verdict, notice := s.texts.Decline, s.texts.Declined
+	if approve {
+		verdict, notice = s.texts.Approve, s.texts.Approved
+	}
+	if msg := c.CallbackQuery().Message; msg != nil {
+		by := c.CallbackQuery().From.FirstName
+		b := NewTextBuilder().Text(msg.Text + "\n\n").Bold("✓ " + verdict + ": " + by)
+		entities := append(msg.Entities[:len(msg.Entities):len(msg.Entities)], b.Entities()...)
+		if err := c.EditMessage(b.String(), OptEntities(entities)); err != nil {
+			return err
+		}
+	}
+	_, err = c.Client.SendMessage(strconv.Itoa(userID), "", notice)
+	return err
+}
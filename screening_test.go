@@ -0,0 +1,262 @@
+package tbot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScreening(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		method := strings.TrimPrefix(r.URL.Path, "/bottoken/")
+		calls = append(calls, method+" "+r.Form.Get("chat_id"))
+		if method == "getChatMember" {
+			status := "member"
+			if r.Form.Get("user_id") == "1" {
+				status = "administrator"
+			}
+			_, _ = fmt.Fprintf(w, `{"ok":true,"result":{"status":%q,"user":{"id":1}}}`, status)
+			return
+		}
+		if strings.HasPrefix(method, "send") || strings.HasPrefix(method, "edit") {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	router := NewRouter(NewClient("token", srv.URL))
+	var passed int
+	router.OnMessage(func(c *Context) error {
+		passed++
+		return nil
+	})
+	NewScreening("join", NewMemoryStorage(), []ScreeningQuestion{
+		{ID: "rules", Text: "Accept the rules?", Options: []string{"Yes", "No"}},
+		{ID: "about", Text: "About you?"},
+	}, WithAdminReview("-9")).Register(router)
+
+	user := &User{ID: 7, FirstName: "Ani"}
+	private := Chat{ID: 7, Type: "private"}
+	updates := []*Update{
+		{Message: &Message{Chat: private, From: user, Text: "hi before screening"}},
+		{ChatJoinRequest: &ChatJoinRequest{Chat: Chat{ID: -5, Title: "Group"}, From: user}},
+		{CallbackQuery: &CallbackQuery{ID: "q", From: user, Data: "join:a:0:0", Message: &Message{Chat: private, MessageID: 1}}},
+		{Message: &Message{Chat: private, From: user, Text: "I like Go"}},
+		{Message: &Message{Chat: private, From: user, Text: "hi after screening"}},
+		{CallbackQuery: &CallbackQuery{ID: "r", From: &User{ID: 1}, Data: "join:r:-5:7:y", Message: &Message{Chat: Chat{ID: -9}, MessageID: 2}}},
+	}
+	for _, u := range updates {
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate() error = %v", err)
+		}
+	}
+
+	want := []string{
+		"sendMessage 7",             // intro
+		"sendMessage 7",             // rules question
+		"editMessageText 7",         // chosen option
+		"sendMessage 7",             // about question
+		"answerCallbackQuery ",      // auto answer
+		"sendMessage -9",            // review in admin chat
+		"sendMessage 7",             // answers sent notice
+		"getChatMember -9",          // reviewer is an admin
+		"approveChatJoinRequest -5", // admin approves
+		"editMessageText -9",        // verdict
+		"sendMessage 7",             // approval notice
+		"answerCallbackQuery ",      // auto answer
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q\nwant %q", calls, want)
+	}
+	if passed != 2 {
+		t.Errorf("messages passed to OnMessage = %d, want 2", passed)
+	}
+}
+
+func TestScreening_forgedReview(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		method := strings.TrimPrefix(r.URL.Path, "/bottoken/")
+		calls = append(calls, method+" "+r.Form.Get("chat_id"))
+		if method == "getChatMember" {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":{"status":"member","user":{"id":8}}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name   string
+		review string
+		query  *CallbackQuery
+		want   []string
+	}{
+		{
+			name:   "from private chat",
+			review: "-9",
+			query:  &CallbackQuery{ID: "q", From: &User{ID: 7}, Data: "join:r:-5:7:y", Message: &Message{Chat: Chat{ID: 7}}},
+			want:   []string{"answerCallbackQuery "},
+		},
+		{
+			name:   "by non-admin in admin chat",
+			review: "-9",
+			query:  &CallbackQuery{ID: "q", From: &User{ID: 8}, Data: "join:r:-5:7:y", Message: &Message{Chat: Chat{ID: -9}}},
+			want:   []string{"getChatMember -9", "answerCallbackQuery "},
+		},
+		{
+			name:  "without admin review",
+			query: &CallbackQuery{ID: "q", From: &User{ID: 7}, Data: "join:r:-5:7:y", Message: &Message{Chat: Chat{ID: 7}}},
+			want:  []string{"answerCallbackQuery "},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = nil
+			router := NewRouter(NewClient("token", srv.URL))
+			var opts []ScreeningOption
+			if tt.review != "" {
+				opts = append(opts, WithAdminReview(tt.review))
+			}
+			NewScreening("join", NewMemoryStorage(), nil, opts...).Register(router)
+			if err := router.HandleUpdate(context.Background(), &Update{CallbackQuery: tt.query}); err != nil {
+				t.Fatalf("HandleUpdate() error = %v", err)
+			}
+			if !reflect.DeepEqual(calls, tt.want) {
+				t.Errorf("calls = %q, want %q", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreening_removedQuestions(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		calls = append(calls, strings.TrimPrefix(r.URL.Path, "/bottoken/")+" "+r.Form.Get("chat_id"))
+		if strings.HasPrefix(r.URL.Path, "/bottoken/send") {
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	storage := NewMemoryStorage()
+	user := &User{ID: 7}
+	saved := &Screening{name: "join", storage: storage}
+	// saved by a deployment asking three questions
+	if err := saved.save(&ScreeningApplication{ChatID: -5, User: user, Step: 2, Answers: map[string]string{"rules": "Yes"}}); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	router := NewRouter(NewClient("token", srv.URL))
+	NewScreening("join", storage, []ScreeningQuestion{{ID: "rules", Text: "Accept the rules?"}}).Register(router)
+	u := &Update{Message: &Message{Chat: Chat{ID: 7, Type: "private"}, From: user, Text: "answer"}}
+	if err := router.HandleUpdate(context.Background(), u); err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	want := []string{"approveChatJoinRequest -5", "sendMessage 7"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestScreening_failedFinish(t *testing.T) {
+	var calls []string
+	approveFails := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		method := strings.TrimPrefix(r.URL.Path, "/bottoken/")
+		calls = append(calls, method+" "+r.Form.Get("chat_id"))
+		switch {
+		case method == "approveChatJoinRequest" && approveFails:
+			_, _ = fmt.Fprint(w, `{"ok":false,"error_code":500,"description":"Internal Server Error"}`)
+		case strings.HasPrefix(method, "send"):
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+		default:
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+		}
+	}))
+	defer srv.Close()
+
+	router := NewRouter(NewClient("token", srv.URL))
+	NewScreening("join", NewMemoryStorage(), []ScreeningQuestion{{ID: "about", Text: "About you?"}}).Register(router)
+	user := &User{ID: 7}
+	join := &Update{ChatJoinRequest: &ChatJoinRequest{Chat: Chat{ID: -5, Title: "Group"}, From: user}}
+	answer := &Update{Message: &Message{Chat: Chat{ID: 7, Type: "private"}, From: user, Text: "I like Go"}}
+	if err := router.HandleUpdate(context.Background(), join); err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	if err := router.HandleUpdate(context.Background(), answer); err == nil {
+		t.Fatal("HandleUpdate() with failing approval succeeded")
+	}
+	approveFails = false
+	calls = nil
+	retry := &Update{Message: &Message{Chat: Chat{ID: 7, Type: "private"}, From: user, Text: "hello?"}}
+	if err := router.HandleUpdate(context.Background(), retry); err != nil {
+		t.Fatalf("HandleUpdate() error = %v", err)
+	}
+	if want := []string{"approveChatJoinRequest -5", "sendMessage 7"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls after retry = %q, want %q", calls, want)
+	}
+
+	unknown := NewScreening("join", NewMemoryStorage(), nil, WithScreeningDecision(func(c *Context, app *ScreeningApplication) (ScreeningDecision, error) {
+		return ScreeningDecision(42), nil
+	}))
+	router = NewRouter(NewClient("token", srv.URL))
+	unknown.Register(router)
+	if err := router.HandleUpdate(context.Background(), join); err == nil || !strings.Contains(err.Error(), "unknown screening decision") {
+		t.Errorf("HandleUpdate() with unknown decision error = %v", err)
+	}
+}
+
+func TestScreening_HandleJoinRequest(t *testing.T) {
+	var texts []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.URL.Path == "/bottoken/sendMessage" {
+			texts = append(texts, r.Form.Get("text"))
+			_, _ = fmt.Fprint(w, `{"ok":true,"result":{"message_id":1}}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{"ok":true,"result":true}`)
+	}))
+	defer srv.Close()
+
+	questions := []ScreeningQuestion{{ID: "about", Text: "About you?"}}
+	plain := NewScreening("plain", NewMemoryStorage(), questions, WithScreeningTexts(ScreeningTexts{Intro: "Hi 100%!"}))
+	titled := NewScreening("titled", NewMemoryStorage(), questions)
+	router := NewRouter(NewClient("token", srv.URL))
+	plain.Register(router)
+	titled.Register(router)
+	router.OnJoinRequest(func(c *Context) error {
+		if c.JoinRequest().Chat.ID == -5 {
+			return plain.HandleJoinRequest(c)
+		}
+		return titled.HandleJoinRequest(c)
+	})
+
+	for i, chat := range []Chat{{ID: -5, Title: "Plain"}, {ID: -6, Title: "Titled"}} {
+		u := &Update{ChatJoinRequest: &ChatJoinRequest{Chat: chat, From: &User{ID: 7 + i}}}
+		if err := router.HandleUpdate(context.Background(), u); err != nil {
+			t.Fatalf("HandleUpdate() error = %v", err)
+		}
+	}
+	want := []string{
+		"Hi 100%!", "About you?",
+		"You asked to join Titled. Please answer a few questions first.", "About you?",
+	}
+	if !reflect.DeepEqual(texts, want) {
+		t.Errorf("texts = %q\nwant %q", texts, want)
+	}
+}
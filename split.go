@@ -0,0 +1,253 @@
+package tbot
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Length limits of text and caption in UTF-16 code units after entities parsing
+const (
+	MaxMessageLength = 4096
+	MaxCaptionLength = 1024
+)
+
+// splitLongMessageKey marks requests to split, it is never sent to Telegram
+const splitLongMessageKey = "tbot_split_long_message"
+
+// OptSplitLongMessage makes SendMessage and SendPhoto split text over the
+// length limit into several messages instead of failing, see SendLongMessage
+var OptSplitLongMessage = func(r url.Values) { r.Set(splitLongMessageKey, "true") }
+
+// SendLongMessage sends text as several messages when it is longer than
+// MaxMessageLength. Text is split at paragraph, line or word boundaries
+// outside of entities when possible, markup of OptParseModeHTML and
+// OptParseModeMarkdown is converted to entities which are clipped to every
+// chunk. Continuation messages reply to the first one, reply markup is
+// attached to the last one. Options are the same as for SendMessage.
+func (c *Client) SendLongMessage(chatID string, thread_id string, text string, opts ...sendOption) ([]*Message, error) {
+	req := url.Values{}
+	req.Set("chat_id", chatID)
+	thread_id = strings.TrimSpace(thread_id)
+	if thread_id != "" {
+		req.Set("message_thread_id", thread_id)
+	}
+	req.Set("text", text)
+	for _, opt := range opts {
+		opt(req)
+	}
+	req.Del(splitLongMessageKey)
+	return c.sendLongMessage(req)
+}
+
+func (c *Client) sendLongMessage(req url.Values) ([]*Message, error) {
+	text := req.Get("text")
+	if utf16Len(text) <= MaxMessageLength {
+		// markup only gets shorter when parsed
+		msg := &Message{}
+		if err := c.sendRequest("/sendMessage", req, msg); err != nil {
+			return nil, err
+		}
+		return []*Message{msg}, nil
+	}
+	plain, entities, err := parseRequestText(text, req.Get("parse_mode"), req.Get("entities"))
+	if err != nil {
+		return nil, err
+	}
+	return c.sendChunks(req, nil, splitText(plain, entities, MaxMessageLength))
+}
+
+// sendChunks sends text chunks as messages replying to first, or to the
+// first of them if first is nil
+func (c *Client) sendChunks(req url.Values, first *Message, chunks []textChunk) ([]*Message, error) {
+	var sent []*Message
+	for i, chunk := range chunks {
+		r := url.Values{}
+		for _, key := range []string{"chat_id", "message_thread_id", "disable_web_page_preview", "disable_notification", "protect_content"} {
+			if v, ok := req[key]; ok {
+				r[key] = v
+			}
+		}
+		r.Set("text", chunk.text)
+		if len(chunk.entities) > 0 {
+			r.Set("entities", structString(chunk.entities))
+		}
+		if first == nil {
+			for _, key := range []string{"reply_to_message_id", "allow_sending_without_reply"} {
+				if v, ok := req[key]; ok {
+					r[key] = v
+				}
+			}
+		} else {
+			r.Set("reply_to_message_id", strconv.Itoa(first.MessageID))
+			r.Set("allow_sending_without_reply", "true")
+		}
+		if i == len(chunks)-1 && req.Get("reply_markup") != "" {
+			r.Set("reply_markup", req.Get("reply_markup"))
+		}
+		msg := &Message{}
+		if err := c.sendRequest("/sendMessage", r, msg); err != nil {
+			return sent, err
+		}
+		if first == nil {
+			first = msg
+		}
+		sent = append(sent, msg)
+	}
+	return sent, nil
+}
+
+// parseRequestText returns plain text and entities of text sent with parse
+// mode or entities request parameters
+func parseRequestText(text, parseMode, entities string) (string, []*MessageEntity, error) {
+	switch parseMode {
+	case "":
+		var parsed []*MessageEntity
+		if entities != "" {
+			if err := json.Unmarshal([]byte(entities), &parsed); err != nil {
+				return "", nil, err
+			}
+		}
+		return text, parsed, nil
+	case ParseModeHTML:
+		return ParseHTML(text)
+	case ParseModeMarkdownV2:
+		return ParseMarkdownV2(text)
+	default:
+		return "", nil, fmt.Errorf("tbot: cannot split text with parse mode %s", parseMode)
+	}
+}
+
+type textChunk struct {
+	text     string
+	entities []*MessageEntity
+}
+
+// splitText splits text into chunks, limits[i] is the length limit of chunk
+// i, the last limit applies to the rest of chunks
+func splitText(text string, entities []*MessageEntity, limits ...int) []textChunk {
+	units := utf16.Encode([]rune(text))
+	var chunks []textChunk
+	for start := 0; start < len(units); {
+		limit := limits[len(limits)-1]
+		if len(chunks) < len(limits) {
+			limit = limits[len(chunks)]
+		}
+		end, next := len(units), len(units)
+		if len(units)-start > limit {
+			end, next = cutText(units, entities, start, limit)
+		}
+		chunks = append(chunks, textChunk{
+			text:     string(utf16.Decode(units[start:end])),
+			entities: clipEntities(entities, start, end),
+		})
+		start = next
+	}
+	return chunks
+}
+
+var textSeparators = []string{"\n\n", "\n", " "}
+
+// cutText finds where the chunk starting at start ends and where the next
+// one starts. Separators found in the second half of the chunk and outside
+// of entities are preferred, separators inside of entities next, a hard cut
+// is the last resort.
+func cutText(units []uint16, entities []*MessageEntity, start, limit int) (end, next int) {
+	max := start + limit
+	if r := units[max]; r >= 0xdc00 && r < 0xe000 && max > start+1 {
+		// do not split a surrogate pair
+		max--
+	}
+	insideEntity := func(pos int) bool {
+		for _, e := range entities {
+			if e.Offset < pos && e.Offset+e.Length > pos {
+				return true
+			}
+		}
+		return false
+	}
+	for _, outside := range []bool{true, false} {
+		for i, sep := range textSeparators {
+			sepUnits := utf16.Encode([]rune(sep))
+			min := start + 1
+			if i < len(textSeparators)-1 && outside {
+				min = start + limit/2
+			}
+			for pos := max; pos >= min; pos-- {
+				if !unitsHavePrefix(units[pos:], sepUnits) {
+					continue
+				}
+				if outside && (insideEntity(pos) || insideEntity(pos+len(sepUnits))) {
+					continue
+				}
+				return pos, pos + len(sepUnits)
+			}
+		}
+	}
+	return max, max
+}
+
+func unitsHavePrefix(units, prefix []uint16) bool {
+	if len(units) < len(prefix) {
+		return false
+	}
+	for i, u := range prefix {
+		if units[i] != u {
+			return false
+		}
+	}
+	return true
+}
+
+// clipEntities returns entities cut to [start, end) with offsets relative to start
+func clipEntities(entities []*MessageEntity, start, end int) []*MessageEntity {
+	var clipped []*MessageEntity
+	for _, e := range entities {
+		from, to := e.Offset, e.Offset+e.Length
+		if from < start {
+			from = start
+		}
+		if to > end {
+			to = end
+		}
+		if from >= to {
+			continue
+		}
+		c := *e
+		c.Offset, c.Length = from-start, to-from
+		clipped = append(clipped, &c)
+	}
+	return clipped
+}
+
+// sendPhotoWithOverflow sends photo with the caption cut to MaxCaptionLength,
+// the rest of the caption is sent as text messages replying to the photo
+func (c *Client) sendPhotoWithOverflow(req url.Values) (*Message, error) {
+	plain, entities, err := parseRequestText(req.Get("caption"), req.Get("parse_mode"), req.Get("caption_entities"))
+	if err != nil {
+		return nil, err
+	}
+	chunks := splitText(plain, entities, MaxCaptionLength, MaxMessageLength)
+	r := url.Values{}
+	for key, v := range req {
+		r[key] = v
+	}
+	r.Del("parse_mode")
+	if len(chunks) > 1 {
+		r.Del("reply_markup")
+	}
+	r.Del("caption_entities")
+	r.Set("caption", chunks[0].text)
+	if len(chunks[0].entities) > 0 {
+		r.Set("caption_entities", structString(chunks[0].entities))
+	}
+	msg := &Message{}
+	if err := c.sendRequest("/sendPhoto", r, msg); err != nil {
+		return msg, err
+	}
+	_, err = c.sendChunks(req, msg, chunks[1:])
+	return msg, err
+}
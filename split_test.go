@@ -0,0 +1,121 @@
+package tbot
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		entities []*MessageEntity
+		limit    int
+		want     []string
+	}{
+		{name: "fits", text: "short", limit: 10, want: []string{"short"}},
+		{name: "paragraph", text: "aaaa bbb\n\ncc dd", limit: 12, want: []string{"aaaa bbb", "cc dd"}},
+		{name: "line", text: "aaaa\nbbbb cc\ndd", limit: 12, want: []string{"aaaa\nbbbb cc", "dd"}},
+		{name: "word", text: "aaaa bbbb cccc", limit: 10, want: []string{"aaaa bbbb", "cccc"}},
+		{name: "hard cut", text: "aaaaaaaaaaaa", limit: 5, want: []string{"aaaaa", "aaaaa", "aa"}},
+		{name: "surrogate pair", text: "aaaa😀b", limit: 5, want: []string{"aaaa", "😀b"}},
+		{
+			name:     "avoids entity",
+			text:     "aaa bbbbb cc dd",
+			entities: []*MessageEntity{{Type: EntityBold, Offset: 4, Length: 11}},
+			limit:    14,
+			want:     []string{"aaa", "bbbbb cc dd"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, chunk := range splitText(tt.text, tt.entities, tt.limit) {
+				got = append(got, chunk.text)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMarkup(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) (string, []*MessageEntity, error)
+		in    string
+		text  string
+		want  string
+	}{
+		{
+			name:  "html",
+			parse: ParseHTML,
+			in:    `<b>a &lt;b&gt; <i>c</i></b> <pre><code class="language-go">x</code></pre> <a href="tg://user?id=7">joe</a>`,
+			text:  "a <b> c x joe",
+			want: `[{"type":"bold","offset":0,"length":7},{"type":"italic","offset":6,"length":1},` +
+				`{"type":"pre","offset":8,"length":1,"language":"go"},` +
+				`{"type":"text_mention","offset":10,"length":3,"user":{"id":7,"is_bot":false,"first_name":"","last_name":"","username":"","language_code":"","can_join_groups":false,"can_read_all_group_messages":false,"supports_inline_queries":false}}]`,
+		},
+		{
+			name:  "markdown",
+			parse: ParseMarkdownV2,
+			in:    "*a \\*b _c_\r* `x\\`` [l](https://x.com/\\)) ```go\ny\n```\n>q1\n>q2\nz",
+			text:  "a *b c x` l y\nq1\nq2\nz",
+			want: `[{"type":"bold","offset":0,"length":6},{"type":"italic","offset":5,"length":1},` +
+				`{"type":"code","offset":7,"length":2},{"type":"text_link","offset":10,"length":1,"url":"https://x.com/)"},` +
+				`{"type":"pre","offset":12,"length":1,"language":"go"},{"type":"blockquote","offset":14,"length":5}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, entities, err := tt.parse(tt.in)
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+			if text != tt.text {
+				t.Errorf("text = %q, want %q", text, tt.text)
+			}
+			if got := structString(entities); got != tt.want {
+				t.Errorf("entities = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_SendLongMessage(t *testing.T) {
+	var sent []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		sent = append(sent, map[string]string{
+			"text":     r.Form.Get("text"),
+			"entities": r.Form.Get("entities"),
+			"reply":    r.Form.Get("reply_to_message_id"),
+			"markup":   r.Form.Get("reply_markup"),
+		})
+		_, _ = fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d}}`, len(sent))
+	}))
+	defer srv.Close()
+
+	para := strings.Repeat("x", 3000)
+	text := "<b>" + para + "</b>\n\n<i>" + para + "</i>"
+	msgs, err := NewClient("token", srv.URL).SendLongMessage("1", "", text,
+		OptParseModeHTML, OptInlineKeyboardMarkup(&InlineKeyboardMarkup{}))
+	if err != nil {
+		t.Fatalf("SendLongMessage() error = %v", err)
+	}
+	if len(msgs) != 2 || len(sent) != 2 {
+		t.Fatalf("sent %d messages, want 2", len(sent))
+	}
+	want := []map[string]string{
+		{"text": para, "entities": `[{"type":"bold","offset":0,"length":3000}]`, "reply": "", "markup": ""},
+		{"text": para, "entities": `[{"type":"italic","offset":0,"length":3000}]`, "reply": "1", "markup": `{"inline_keyboard":null}`},
+	}
+	if !reflect.DeepEqual(sent, want) {
+		t.Errorf("sent = %v, want %v", sent, want)
+	}
+}
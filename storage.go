@@ -0,0 +1,69 @@
+package tbot
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by Storage.Get when key does not exist or is expired
+var ErrNotFound = errors.New("tbot: key not found")
+
+// Storage is a key-value store used to persist bot state such as update
+// offsets. Implementations must be safe for concurrent use.
+type Storage interface {
+	// Get returns value of the key or ErrNotFound
+	Get(key string) ([]byte, error)
+	// Set stores value under the key. Zero ttl means the key never expires.
+	Set(key string, value []byte, ttl time.Duration) error
+	// Delete removes the key, deleting a missing key is not an error
+	Delete(key string) error
+}
+
+type memoryItem struct {
+	value   []byte
+	expires time.Time
+}
+
+// MemoryStorage is an in-memory Storage, its content is lost on restart
+type MemoryStorage struct {
+	mu    sync.RWMutex
+	items map[string]memoryItem
+}
+
+// NewMemoryStorage creates empty MemoryStorage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{items: map[string]memoryItem{}}
+}
+
+func (s *MemoryStorage) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	item, ok := s.items[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !item.expires.IsZero() && time.Now().After(item.expires) {
+		_ = s.Delete(key)
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), item.value...), nil
+}
+
+func (s *MemoryStorage) Set(key string, value []byte, ttl time.Duration) error {
+	item := memoryItem{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		item.expires = time.Now().Add(ttl)
+	}
+	s.mu.Lock()
+	s.items[key] = item
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStorage) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.items, key)
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,172 @@
+package tbot
+
+import (
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Message entity types
+const (
+	EntityMention       = "mention"
+	EntityHashtag       = "hashtag"
+	EntityCashtag       = "cashtag"
+	EntityBotCommand    = "bot_command"
+	EntityURL           = "url"
+	EntityEmail         = "email"
+	EntityPhoneNumber   = "phone_number"
+	EntityBold          = "bold"
+	EntityItalic        = "italic"
+	EntityUnderline     = "underline"
+	EntityStrikethrough = "strikethrough"
+	EntitySpoiler       = "spoiler"
+	EntityBlockquote    = "blockquote"
+	EntityCode          = "code"
+	EntityPre           = "pre"
+	EntityTextLink      = "text_link"
+	EntityTextMention   = "text_mention"
+	EntityCustomEmoji   = "custom_emoji"
+)
+
+var (
+	OptEntities = func(entities []*MessageEntity) sendOption {
+		return func(r url.Values) {
+			r.Set("entities", structString(entities))
+		}
+	}
+	OptCaptionEntities = func(entities []*MessageEntity) sendOption {
+		return func(r url.Values) {
+			r.Set("caption_entities", structString(entities))
+		}
+	}
+)
+
+// utf16Len returns length of s in UTF-16 code units, the unit of entity offsets
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 && r <= unicode.MaxRune {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// TextBuilder builds message text together with its entities, so no parse
+// mode and no escaping is needed. Offsets are counted in UTF-16 code units.
+//
+//	b := tbot.NewTextBuilder().Bold("Alert").Text(": ").Code(logLine)
+//	client.SendMessage(chatID, "", b.String(), tbot.OptEntities(b.Entities()))
+type TextBuilder struct {
+	text     strings.Builder
+	length   int
+	entities []*MessageEntity
+}
+
+// NewTextBuilder creates empty TextBuilder
+func NewTextBuilder() *TextBuilder {
+	return &TextBuilder{}
+}
+
+// String returns the plain text
+func (b *TextBuilder) String() string {
+	return b.text.String()
+}
+
+// Entities returns entities of the text ordered by offset
+func (b *TextBuilder) Entities() []*MessageEntity {
+	return b.entities
+}
+
+// Len returns length of the text in UTF-16 code units
+func (b *TextBuilder) Len() int {
+	return b.length
+}
+
+// Text appends plain text
+func (b *TextBuilder) Text(s string) *TextBuilder {
+	b.text.WriteString(s)
+	b.length += utf16Len(s)
+	return b
+}
+
+// Entity appends text produced by fn wrapped into entity. Offset and length
+// of entity are set by the builder, other fields are kept, e.g.
+//
+//	b.Entity(&tbot.MessageEntity{Type: tbot.EntityBold}, func(b *tbot.TextBuilder) {
+//		b.Text("bold and ").Italic("italic")
+//	})
+func (b *TextBuilder) Entity(entity *MessageEntity, fn func(b *TextBuilder)) *TextBuilder {
+	e := *entity
+	e.Offset = b.length
+	b.entities = append(b.entities, &e)
+	index := len(b.entities) - 1
+	fn(b)
+	e.Length = b.length - e.Offset
+	if e.Length == 0 {
+		// empty entities are rejected by Telegram
+		b.entities = append(b.entities[:index], b.entities[index+1:]...)
+	}
+	return b
+}
+
+func (b *TextBuilder) styled(entity *MessageEntity, s string) *TextBuilder {
+	return b.Entity(entity, func(b *TextBuilder) { b.Text(s) })
+}
+
+// Bold appends bold text
+func (b *TextBuilder) Bold(s string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityBold}, s)
+}
+
+// Italic appends italic text
+func (b *TextBuilder) Italic(s string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityItalic}, s)
+}
+
+// Underline appends underlined text
+func (b *TextBuilder) Underline(s string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityUnderline}, s)
+}
+
+// Strikethrough appends strikethrough text
+func (b *TextBuilder) Strikethrough(s string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityStrikethrough}, s)
+}
+
+// Spoiler appends text hidden under a spoiler
+func (b *TextBuilder) Spoiler(s string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntitySpoiler}, s)
+}
+
+// Code appends monowidth inline code
+func (b *TextBuilder) Code(s string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityCode}, s)
+}
+
+// Pre appends monowidth code block, language may be empty
+func (b *TextBuilder) Pre(s, language string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityPre, Language: language}, s)
+}
+
+// Link appends text linking to url
+func (b *TextBuilder) Link(s, url string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityTextLink, URL: url}, s)
+}
+
+// Mention appends text mentioning user, works for users without username
+func (b *TextBuilder) Mention(s string, user *User) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityTextMention, User: user}, s)
+}
+
+// CustomEmoji appends custom emoji, emoji is shown where custom emoji are not supported
+func (b *TextBuilder) CustomEmoji(emoji, customEmojiID string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityCustomEmoji, CustomEmojiID: customEmojiID}, emoji)
+}
+
+// Blockquote appends quoted text
+func (b *TextBuilder) Blockquote(s string) *TextBuilder {
+	return b.styled(&MessageEntity{Type: EntityBlockquote}, s)
+}
@@ -0,0 +1,31 @@
+package tbot
+
+import (
+	"testing"
+)
+
+func TestTextBuilder(t *testing.T) {
+	b := NewTextBuilder().
+		Text("😀 ").
+		Bold("bold").
+		Text(" ").
+		Entity(&MessageEntity{Type: EntityItalic}, func(b *TextBuilder) {
+			b.Text("a_b ").Link("𝕏.com", "https://x.com")
+		}).
+		Code("").
+		Pre("x := 1", "go")
+
+	if got, want := b.String(), "😀 bold a_b 𝕏.comx := 1"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	want := `[{"type":"bold","offset":3,"length":4},` +
+		`{"type":"italic","offset":8,"length":10},` +
+		`{"type":"text_link","offset":12,"length":6,"url":"https://x.com"},` +
+		`{"type":"pre","offset":18,"length":6,"language":"go"}]`
+	if got := structString(b.Entities()); got != want {
+		t.Errorf("Entities() = %s, want %s", got, want)
+	}
+	if got := b.Len(); got != 24 {
+		t.Errorf("Len() = %d, want 24", got)
+	}
+}
@@ -21,6 +21,10 @@ type Chat struct {
 	LinkedChatID                int              `json:"linked_chat_id"`
 	Location                    *ChatLocation    `json:"location"`
 	AllMembersAreAdministrators bool             `json:"all_members_are_administrators"`
+	IsForum                     bool             `json:"is_forum,omitempty"`
+	JoinToSendMessages          bool             `json:"join_to_send_messages,omitempty"`
+	JoinByRequest               bool             `json:"join_by_request,omitempty"`
+	HasProtectedContent         bool             `json:"has_protected_content,omitempty"`
 }
 
 // ChatLocation Represents a location to which a chat is connected.
@@ -32,12 +36,13 @@ type ChatLocation struct {
 // MessageEntity represents one special entity in a text message.
 // For example, hashtags, usernames, URLs, etc.
 type MessageEntity struct {
-	Type     string `json:"type"`
-	Offset   int    `json:"offset"`
-	Length   int    `json:"length"`
-	URL      string `json:"url"`
-	User     *User  `json:"user,omitempty"`
-	Language string `json:"language"`
+	Type          string `json:"type"`
+	Offset        int    `json:"offset"`
+	Length        int    `json:"length"`
+	URL           string `json:"url,omitempty"`
+	User          *User  `json:"user,omitempty"`
+	Language      string `json:"language,omitempty"`
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
 }
 
 // Audio represents an audio file to bea treated as music by Telegram clients
@@ -309,6 +314,8 @@ type VoiceChatParticipantsInvited struct {
 // Message represents a message
 type Message struct {
 	MessageID                     int                            `json:"message_id"`
+	MessageThreadID               int                            `json:"message_thread_id,omitempty"`
+	IsTopicMessage                bool                           `json:"is_topic_message,omitempty"`
 	From                          *User                          `json:"from,omitempty"`
 	SenderChat                    *Chat                          `json:"sender_chat,omitempty"`
 	Date                          int64                          `json:"date"`
@@ -364,36 +371,112 @@ type Message struct {
 	VoiceChatStarted              *VoiceChatStarted              `json:"voice_chat_started,omitempty"`
 	VoiceChatEnded                *VoiceChatEnded                `json:"voice_chat_ended,omitempty"`
 	VoiceChatParticipantsInvited  *VoiceChatParticipantsInvited  `json:"voice_chat_participants_invited,omitempty"`
+	ForumTopicCreated             *ForumTopicCreated             `json:"forum_topic_created,omitempty"`
+	ForumTopicEdited              *ForumTopicEdited              `json:"forum_topic_edited,omitempty"`
+	ForumTopicClosed              *ForumTopicClosed              `json:"forum_topic_closed,omitempty"`
+	ForumTopicReopened            *ForumTopicReopened            `json:"forum_topic_reopened,omitempty"`
+	GeneralForumTopicHidden       *GeneralForumTopicHidden       `json:"general_forum_topic_hidden,omitempty"`
+	GeneralForumTopicUnhidden     *GeneralForumTopicUnhidden     `json:"general_forum_topic_unhidden,omitempty"`
+}
+
+// Update represents an incoming update.
+// At most one of the optional fields can be present in any given update.
+type Update struct {
+	UpdateID           int                 `json:"update_id"`
+	Message            *Message            `json:"message,omitempty"`
+	EditedMessage      *Message            `json:"edited_message,omitempty"`
+	ChannelPost        *Message            `json:"channel_post,omitempty"`
+	EditedChannelPost  *Message            `json:"edited_channel_post,omitempty"`
+	InlineQuery        *InlineQuery        `json:"inline_query,omitempty"`
+	ChosenInlineResult *ChosenInlineResult `json:"chosen_inline_result,omitempty"`
+	CallbackQuery      *CallbackQuery      `json:"callback_query,omitempty"`
+	ChatJoinRequest    *ChatJoinRequest    `json:"chat_join_request,omitempty"`
+	MyChatMember       *ChatMemberUpdated  `json:"my_chat_member,omitempty"`
+	ChatMember         *ChatMemberUpdated  `json:"chat_member,omitempty"`
+}
+
+// CallbackQuery represents an incoming callback query from a callback button
+// in an inline keyboard
+type CallbackQuery struct {
+	ID              string   `json:"id"`
+	From            *User    `json:"from"`
+	Message         *Message `json:"message,omitempty"`
+	InlineMessageID string   `json:"inline_message_id,omitempty"`
+	ChatInstance    string   `json:"chat_instance"`
+	Data            string   `json:"data,omitempty"`
+	GameShortName   string   `json:"game_short_name,omitempty"`
 }
 
 type InlineKeyboardButton struct {
-	Text                         string    `json:"text"`
-	URL                          string    `json:"url,omitempty"`
-	LoginURL                     *LoginURL `json:"login_url,omitempty"`
-	CallbackData                 string    `json:"callback_data,omitempty"`
-	SwitchInlineQuery            *string   `json:"switch_inline_query"`
-	SwitchInlineQueryCurrentChat *string   `json:"switch_inline_query_current_chat,omitempty"`
-}
+	Text                         string                       `json:"text"`
+	URL                          string                       `json:"url,omitempty"`
+	CallbackData                 string                       `json:"callback_data,omitempty"`
+	WebApp                       *WebAppInfo                  `json:"web_app,omitempty"`
+	LoginURL                     *LoginURL                    `json:"login_url,omitempty"`
+	SwitchInlineQuery            *string                      `json:"switch_inline_query,omitempty"`
+	SwitchInlineQueryCurrentChat *string                      `json:"switch_inline_query_current_chat,omitempty"`
+	SwitchInlineQueryChosenChat  *SwitchInlineQueryChosenChat `json:"switch_inline_query_chosen_chat,omitempty"`
+	CallbackGame                 *CallbackGame                `json:"callback_game,omitempty"`
+	Pay                          bool                         `json:"pay,omitempty"`
+}
+
+// SwitchInlineQueryChosenChat represents an inline button that switches the
+// current user to inline mode in a chosen chat, with an optional default inline query
+type SwitchInlineQueryChosenChat struct {
+	Query             string `json:"query,omitempty"`
+	AllowUserChats    bool   `json:"allow_user_chats,omitempty"`
+	AllowBotChats     bool   `json:"allow_bot_chats,omitempty"`
+	AllowGroupChats   bool   `json:"allow_group_chats,omitempty"`
+	AllowChannelChats bool   `json:"allow_channel_chats,omitempty"`
+}
+
+// CallbackGame is a placeholder, currently holds no information
+type CallbackGame struct{}
 
 type InlineKeyboardMarkup struct {
 	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
 }
 
-type KeyboardButtonPoolType struct {
-	Type string `json:"type"`
+// KeyboardButtonPollType represents type of a poll, which is allowed to be
+// created and sent when the corresponding button is pressed
+type KeyboardButtonPollType struct {
+	Type string `json:"type,omitempty"`
+}
+
+// KeyboardButtonRequestUsers defines the criteria used to request suitable users
+type KeyboardButtonRequestUsers struct {
+	RequestID     int   `json:"request_id"`
+	UserIsBot     *bool `json:"user_is_bot,omitempty"`
+	UserIsPremium *bool `json:"user_is_premium,omitempty"`
+	MaxQuantity   int   `json:"max_quantity,omitempty"`
+}
+
+// KeyboardButtonRequestChat defines the criteria used to request a suitable chat
+type KeyboardButtonRequestChat struct {
+	RequestID       int   `json:"request_id"`
+	ChatIsChannel   bool  `json:"chat_is_channel"`
+	ChatIsForum     *bool `json:"chat_is_forum,omitempty"`
+	ChatHasUsername *bool `json:"chat_has_username,omitempty"`
+	ChatIsCreated   bool  `json:"chat_is_created,omitempty"`
+	BotIsMember     bool  `json:"bot_is_member,omitempty"`
 }
 
 // KeyboardButton represents one button of the reply keyboard
 type KeyboardButton struct {
-	Text            string                  `json:"text"`
-	RequestContact  bool                    `json:"request_contact"`
-	RequestLocation bool                    `json:"request_location"`
-	RequestPool     *KeyboardButtonPoolType `json:"request_pool,omitempty"`
+	Text            string                      `json:"text"`
+	RequestUsers    *KeyboardButtonRequestUsers `json:"request_users,omitempty"`
+	RequestChat     *KeyboardButtonRequestChat  `json:"request_chat,omitempty"`
+	RequestContact  bool                        `json:"request_contact,omitempty"`
+	RequestLocation bool                        `json:"request_location,omitempty"`
+	RequestPoll     *KeyboardButtonPollType     `json:"request_poll,omitempty"`
+	WebApp          *WebAppInfo                 `json:"web_app,omitempty"`
 }
 
 type ReplyKeyboardMarkup struct {
-	Keyboard        []KeyboardButton `json:"keyboard"`
-	ResizeKeyboard  bool             `json:"resize_keyboard"`
-	OneTimeKeyboard bool             `json:"one_time_keyboard"`
-	Selective       bool             `json:"selective"`
+	Keyboard              [][]KeyboardButton `json:"keyboard"`
+	IsPersistent          bool               `json:"is_persistent,omitempty"`
+	ResizeKeyboard        bool               `json:"resize_keyboard"`
+	OneTimeKeyboard       bool               `json:"one_time_keyboard"`
+	InputFieldPlaceholder string             `json:"input_field_placeholder,omitempty"`
+	Selective             bool               `json:"selective"`
 }
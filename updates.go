@@ -0,0 +1,154 @@
+package tbot
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// UpdateHandler handles a single update. It is used both by update sources
+// (polling, webhook) to hand over updates and by WorkerPool to process them.
+type UpdateHandler func(ctx context.Context, u *Update) error
+
+// Chat returns the chat the update belongs to, or nil if the update is not bound to a chat
+func (u *Update) Chat() *Chat {
+	if msg := u.message(); msg != nil {
+		return &msg.Chat
+	}
+	if u.CallbackQuery != nil && u.CallbackQuery.Message != nil {
+		return &u.CallbackQuery.Message.Chat
+	}
+	if u.ChatJoinRequest != nil {
+		return &u.ChatJoinRequest.Chat
+	}
+	if cmu := u.chatMemberUpdated(); cmu != nil {
+		return &cmu.Chat
+	}
+	return nil
+}
+
+// From returns the user who caused the update, or nil if there is none
+func (u *Update) From() *User {
+	if msg := u.message(); msg != nil {
+		return msg.From
+	}
+	switch {
+	case u.CallbackQuery != nil:
+		return u.CallbackQuery.From
+	case u.InlineQuery != nil:
+		return u.InlineQuery.From
+	case u.ChosenInlineResult != nil:
+		return u.ChosenInlineResult.From
+	case u.ChatJoinRequest != nil:
+		return u.ChatJoinRequest.From
+	case u.chatMemberUpdated() != nil:
+		return u.chatMemberUpdated().From
+	}
+	return nil
+}
+
+func (u *Update) chatMemberUpdated() *ChatMemberUpdated {
+	if u.MyChatMember != nil {
+		return u.MyChatMember
+	}
+	return u.ChatMember
+}
+
+func (u *Update) message() *Message {
+	switch {
+	case u.Message != nil:
+		return u.Message
+	case u.EditedMessage != nil:
+		return u.EditedMessage
+	case u.ChannelPost != nil:
+		return u.ChannelPost
+	case u.EditedChannelPost != nil:
+		return u.EditedChannelPost
+	}
+	return nil
+}
+
+// GetUpdates receives incoming updates using long polling.
+// timeout is in seconds, 0 means short polling.
+func (c *Client) GetUpdates(ctx context.Context, offset, limit, timeout int) ([]*Update, error) {
+	req := url.Values{}
+	for k, v := range c.updateParams {
+		req[k] = v
+	}
+	if offset != 0 {
+		req.Set("offset", strconv.Itoa(offset))
+	}
+	if limit > 0 {
+		req.Set("limit", strconv.Itoa(limit))
+	}
+	req.Set("timeout", strconv.Itoa(timeout))
+	var updates []*Update
+	err := c.sendRequestContext(ctx, "/getUpdates", req, &updates)
+	return updates, err
+}
+
+// pollRetryDelay is a pause between getUpdates calls after a failed one
+var pollRetryDelay = 3 * time.Second
+
+// Poll fetches updates using long polling and hands each of them to submit
+// in order, until ctx is done. The next getUpdates call is not made before
+// submit accepts the whole batch, so a blocking submit (e.g. WorkerPool.Submit
+// with a full queue) slows polling down instead of piling updates in memory.
+//
+// With WithOffsetTracker the offset is taken from the tracker, so Telegram
+// keeps updates until their handlers succeed; updates fetched again while
+// still in flight are skipped.
+func (c *Client) Poll(ctx context.Context, submit UpdateHandler) error {
+	if c.offsets != nil {
+		c.nextOffset = c.offsets.Offset()
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var changed <-chan struct{}
+		if c.offsets != nil {
+			changed = c.offsets.Changed()
+			c.nextOffset = c.offsets.Offset()
+		}
+		updates, err := c.GetUpdates(ctx, c.nextOffset, c.bufferSize, c.timeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			c.logger.Errorf("tbot: unable to get updates: %v", err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(pollRetryDelay):
+			}
+			continue
+		}
+		fresh := 0
+		for _, u := range updates {
+			if c.offsets != nil && !c.offsets.Begin(u.UpdateID) {
+				continue
+			}
+			fresh++
+			if err := submit(ctx, u); err != nil {
+				if c.offsets != nil {
+					c.offsets.Abort(u.UpdateID)
+				}
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return err
+			}
+			c.nextOffset = u.UpdateID + 1
+		}
+		if changed != nil && len(updates) > 0 && fresh == 0 {
+			// everything fetched is still in flight, wait for progress
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-changed:
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package tbot
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// WebhookHandler returns http.Handler which decodes updates sent by Telegram
+// and hands them to submit. When submit fails (e.g. the request is cancelled
+// while waiting for a free slot in a full WorkerPool) the handler responds with
+// 503 Service Unavailable so Telegram delivers the update again later.
+// With WithOffsetTracker duplicates are acknowledged without calling submit.
+func (c *Client) WebhookHandler(submit UpdateHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		u := &Update{}
+		if err := json.NewDecoder(r.Body).Decode(u); err != nil {
+			c.logger.Errorf("tbot: unable to decode webhook update: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if c.offsets != nil && !c.offsets.Begin(u.UpdateID) {
+			// redelivery of an update which is in flight or handled already
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		if err := submit(r.Context(), u); err != nil {
+			if c.offsets != nil {
+				c.offsets.Abort(u.UpdateID)
+			}
+			c.logger.Errorf("tbot: unable to submit update %d: %v", u.UpdateID, err)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,231 @@
+package tbot
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrPoolStopped is returned by WorkerPool.Submit after the pool was stopped
+var ErrPoolStopped = errors.New("tbot: worker pool is stopped")
+
+// UpdateKeyFunc returns ordering key of the update. Updates with the same key
+// are handled one by one in the order they were submitted, updates with
+// different keys may be handled in parallel.
+type UpdateKeyFunc func(u *Update) int64
+
+// KeyByChat orders updates within a chat. Updates without a chat are ordered
+// by sender, updates with neither are spread by update id.
+func KeyByChat(u *Update) int64 {
+	if chat := u.Chat(); chat != nil {
+		return int64(chat.ID)
+	}
+	return KeyByUser(u)
+}
+
+// KeyByUser orders updates from the same user regardless of the chat
+func KeyByUser(u *Update) int64 {
+	if from := u.From(); from != nil {
+		return int64(from.ID)
+	}
+	return int64(u.UpdateID)
+}
+
+// WorkerPool handles updates concurrently while keeping per-key ordering.
+// Every worker owns a bounded queue and an update always goes to the queue
+// picked by its key, so updates of one chat (or user) never overtake each other.
+// Submit blocks while the target queue is full, which propagates backpressure
+// to the update source.
+type WorkerPool struct {
+	handler   UpdateHandler
+	key       UpdateKeyFunc
+	size      int
+	queueSize int
+	logger    Logger
+
+	mu       sync.RWMutex
+	queues   []chan *Update
+	stopped  bool
+	quit     chan struct{}
+	quitOnce sync.Once
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// WorkerPoolOption configures WorkerPool
+type WorkerPoolOption func(*WorkerPool)
+
+// WithPoolSize sets number of workers, defaults to runtime.NumCPU()
+func WithPoolSize(size int) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.size = size
+	}
+}
+
+// WithQueueSize sets capacity of each worker queue, defaults to 100
+func WithQueueSize(size int) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.queueSize = size
+	}
+}
+
+// WithPoolKey sets function used to order updates, defaults to KeyByChat
+func WithPoolKey(key UpdateKeyFunc) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.key = key
+	}
+}
+
+// WithPoolLogger sets logger for handler errors and panics
+func WithPoolLogger(logger Logger) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.logger = logger
+	}
+}
+
+// NewWorkerPool creates a pool which passes updates to handler
+func NewWorkerPool(handler UpdateHandler, opts ...WorkerPoolOption) *WorkerPool {
+	p := &WorkerPool{
+		handler:   handler,
+		key:       KeyByChat,
+		size:      runtime.NumCPU(),
+		queueSize: 100,
+		logger:    nopLogger{},
+		quit:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.size < 1 {
+		p.size = 1
+	}
+	if p.queueSize < 0 {
+		p.queueSize = 0
+	}
+	return p
+}
+
+// Start launches workers. Handlers receive a context derived from ctx.
+func (p *WorkerPool) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.queues != nil || p.stopped {
+		return
+	}
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.queues = make([]chan *Update, p.size)
+	for i := range p.queues {
+		q := make(chan *Update, p.queueSize)
+		p.queues[i] = q
+		p.wg.Add(1)
+		go p.work(ctx, q)
+	}
+}
+
+// Submit puts update to the queue of its key. It blocks while the queue is
+// full, until ctx is done or the pool is stopped.
+func (p *WorkerPool) Submit(ctx context.Context, u *Update) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		return ErrPoolStopped
+	}
+	if p.queues == nil {
+		return errors.New("tbot: worker pool is not started")
+	}
+	select {
+	case p.queue(u) <- u:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.quit:
+		return ErrPoolStopped
+	}
+}
+
+// TrySubmit puts update to the queue of its key without blocking.
+// It returns false if the queue is full or the pool is not running.
+func (p *WorkerPool) TrySubmit(u *Update) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped || p.queues == nil {
+		return false
+	}
+	select {
+	case p.queue(u) <- u:
+		return true
+	default:
+		return false
+	}
+}
+
+// Queued returns number of updates waiting in queues
+func (p *WorkerPool) Queued() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	n := 0
+	for _, q := range p.queues {
+		n += len(q)
+	}
+	return n
+}
+
+// Stop stops accepting updates and waits until queued and in-flight updates
+// are handled. If ctx is done first, handlers' context is cancelled, updates
+// left in queues are dropped and ctx error is returned.
+func (p *WorkerPool) Stop(ctx context.Context) error {
+	// wakes up Submit blocked on a full queue, it holds the read lock
+	p.quitOnce.Do(func() { close(p.quit) })
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		for _, q := range p.queues {
+			close(q)
+		}
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		if p.cancel != nil {
+			p.cancel()
+		}
+		return nil
+	case <-ctx.Done():
+		if p.cancel != nil {
+			p.cancel()
+		}
+		return ctx.Err()
+	}
+}
+
+func (p *WorkerPool) queue(u *Update) chan *Update {
+	return p.queues[uint64(p.key(u))%uint64(len(p.queues))]
+}
+
+func (p *WorkerPool) work(ctx context.Context, queue chan *Update) {
+	defer p.wg.Done()
+	for u := range queue {
+		if ctx.Err() != nil {
+			continue
+		}
+		p.handle(ctx, u)
+	}
+}
+
+func (p *WorkerPool) handle(ctx context.Context, u *Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Errorf("tbot: panic while handling update %d: %v", u.UpdateID, r)
+		}
+	}()
+	if err := p.handler(ctx, u); err != nil {
+		p.logger.Errorf("tbot: unable to handle update %d: %v", u.UpdateID, err)
+	}
+}
@@ -0,0 +1,122 @@
+package tbot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWorkerPool_KeepsOrderWithinChat(t *testing.T) {
+	var mu sync.Mutex
+	got := map[int][]int{}
+	handler := func(ctx context.Context, u *Update) error {
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		got[u.Message.Chat.ID] = append(got[u.Message.Chat.ID], u.UpdateID)
+		mu.Unlock()
+		return nil
+	}
+	p := NewWorkerPool(handler, WithPoolSize(4), WithQueueSize(2))
+	p.Start(context.Background())
+
+	chats := []int{-100, -200, 300, 400, 500}
+	for i := 0; i < 50; i++ {
+		u := &Update{UpdateID: i, Message: &Message{Chat: Chat{ID: chats[i%len(chats)]}}}
+		if err := p.Submit(context.Background(), u); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	for _, chat := range chats {
+		ids := got[chat]
+		if len(ids) != 10 {
+			t.Errorf("chat %d handled %d updates, want 10", chat, len(ids))
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i] < ids[i-1] {
+				t.Errorf("chat %d handled out of order: %v", chat, ids)
+				break
+			}
+		}
+	}
+}
+
+func TestWorkerPool_SubmitBlocksWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	p := NewWorkerPool(func(ctx context.Context, u *Update) error {
+		<-release
+		return nil
+	}, WithPoolSize(1), WithQueueSize(1))
+	p.Start(context.Background())
+
+	// first update is taken by the worker, second fills the queue
+	for i := 0; i < 2; i++ {
+		if err := p.Submit(context.Background(), &Update{UpdateID: i}); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.Submit(ctx, &Update{UpdateID: 3}); err != context.DeadlineExceeded {
+		t.Errorf("Submit() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	close(release)
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := p.Submit(context.Background(), &Update{UpdateID: 4}); err != ErrPoolStopped {
+		t.Errorf("Submit() error = %v, want %v", err, ErrPoolStopped)
+	}
+}
+
+func TestWorkerPool_StopWakesBlockedSubmit(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	p := NewWorkerPool(func(ctx context.Context, u *Update) error {
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+		return nil
+	}, WithPoolSize(1), WithQueueSize(1))
+	p.Start(context.Background())
+	for i := 0; i < 2; i++ {
+		if err := p.Submit(context.Background(), &Update{UpdateID: i}); err != nil {
+			t.Fatalf("Submit() error = %v", err)
+		}
+	}
+
+	submitted := make(chan error, 1)
+	go func() {
+		submitted <- p.Submit(context.Background(), &Update{UpdateID: 2})
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- p.Stop(ctx)
+	}()
+	select {
+	case err := <-submitted:
+		if err != ErrPoolStopped {
+			t.Errorf("blocked Submit() error = %v, want %v", err, ErrPoolStopped)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop() did not wake up blocked Submit()")
+	}
+	select {
+	case err := <-stopped:
+		if err != context.DeadlineExceeded {
+			t.Errorf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Stop() ignored its deadline")
+	}
+}